@@ -0,0 +1,68 @@
+package linter
+
+import (
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v2"
+)
+
+// Allowlist suppresses findings by ID, optionally scoped to a specific file.
+// It mirrors .gorilla-pkg-lint.yaml in the project directory.
+type Allowlist struct {
+	Suppress []SuppressRule `yaml:"suppress"`
+}
+
+// SuppressRule drops matching findings. File is optional; when empty, every
+// finding with this ID is suppressed regardless of which file it's in.
+type SuppressRule struct {
+	ID   string `yaml:"id"`
+	File string `yaml:"file,omitempty"`
+}
+
+// LoadAllowlist reads .gorilla-pkg-lint.yaml from projectDir. A missing file
+// is not an error; it just means nothing is suppressed.
+func LoadAllowlist(projectDir string) (*Allowlist, error) {
+	path := filepath.Join(projectDir, ".gorilla-pkg-lint.yaml")
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return &Allowlist{}, nil
+		}
+		return nil, err
+	}
+
+	var allowlist Allowlist
+	if err := yaml.Unmarshal(data, &allowlist); err != nil {
+		return nil, err
+	}
+	return &allowlist, nil
+}
+
+// Filter drops any finding matched by a suppress rule.
+func (a *Allowlist) Filter(findings []Finding) []Finding {
+	if a == nil || len(a.Suppress) == 0 {
+		return findings
+	}
+
+	var kept []Finding
+	for _, f := range findings {
+		if a.suppresses(f) {
+			continue
+		}
+		kept = append(kept, f)
+	}
+	return kept
+}
+
+func (a *Allowlist) suppresses(f Finding) bool {
+	for _, rule := range a.Suppress {
+		if rule.ID != f.ID {
+			continue
+		}
+		if rule.File == "" || rule.File == f.File {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,23 @@
+// Package linter runs pre-packaging checks over a project directory: its
+// build-info.yaml and the scripts/payload trees gorilla-pkg will build from.
+package linter
+
+// Severity classifies how serious a Finding is.
+type Severity string
+
+const (
+	// SeverityError fails a lint run even without --strict.
+	SeverityError Severity = "error"
+	// SeverityWarning only fails a lint run under --strict.
+	SeverityWarning Severity = "warning"
+)
+
+// Finding is a single lint result, identified by a stable ID (e.g. "GP001")
+// so it can be suppressed via .gorilla-pkg-lint.yaml.
+type Finding struct {
+	ID       string
+	Severity Severity
+	File     string
+	Line     int
+	Message  string
+}
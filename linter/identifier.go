@@ -0,0 +1,24 @@
+package linter
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// reverseDNSPattern requires at least three lowercase, dot-separated labels,
+// e.g. "com.example.app".
+var reverseDNSPattern = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*(\.[a-z0-9]+(-[a-z0-9]+)*){2,}$`)
+
+// checkIdentifier warns when product.identifier doesn't look like a
+// reverse-DNS identifier.
+func checkIdentifier(identifier string) []Finding {
+	if reverseDNSPattern.MatchString(identifier) {
+		return nil
+	}
+	return []Finding{{
+		ID:       "GP003",
+		Severity: SeverityWarning,
+		File:     "build-info.yaml",
+		Message:  fmt.Sprintf("product.identifier %q does not look like a reverse-DNS identifier (e.g. com.example.app)", identifier),
+	}}
+}
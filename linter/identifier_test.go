@@ -0,0 +1,20 @@
+package linter
+
+import "testing"
+
+func TestCheckIdentifierValid(t *testing.T) {
+	for _, identifier := range []string{"com.example.app", "io.gorilla-pkg.tool"} {
+		if findings := checkIdentifier(identifier); len(findings) != 0 {
+			t.Errorf("checkIdentifier(%q) = %+v, want none", identifier, findings)
+		}
+	}
+}
+
+func TestCheckIdentifierInvalid(t *testing.T) {
+	for _, identifier := range []string{"myapp", "Com.Example.App", "com.example"} {
+		findings := checkIdentifier(identifier)
+		if len(findings) != 1 || findings[0].ID != "GP003" || findings[0].Severity != SeverityWarning {
+			t.Errorf("checkIdentifier(%q) = %+v, want a single GP003 warning", identifier, findings)
+		}
+	}
+}
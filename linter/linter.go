@@ -0,0 +1,96 @@
+package linter
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/rodchristiansen/gorilla-pkg/packager"
+)
+
+// Lint runs every check against projectDir's build-info.yaml, scripts, and
+// payload, returning the findings that survive the project's
+// .gorilla-pkg-lint.yaml allowlist.
+func Lint(projectDir string) ([]Finding, error) {
+	buildInfo, err := packager.ReadBuildInfo(projectDir)
+	if err != nil {
+		return nil, err
+	}
+
+	var findings []Finding
+
+	findings = append(findings, checkVersion(buildInfo.Product.Version)...)
+	findings = append(findings, checkIdentifier(buildInfo.Product.Identifier)...)
+	findings = append(findings, checkInstallLocation(projectDir, buildInfo.InstallLocation)...)
+
+	conflictFindings, err := checkPostInstallConflict(projectDir, buildInfo.PostInstallAction)
+	if err != nil {
+		return nil, err
+	}
+	findings = append(findings, conflictFindings...)
+
+	scriptFindings, err := checkScripts(projectDir)
+	if err != nil {
+		return nil, err
+	}
+	findings = append(findings, scriptFindings...)
+
+	payloadFindings, err := checkPayload(projectDir)
+	if err != nil {
+		return nil, err
+	}
+	findings = append(findings, payloadFindings...)
+
+	allowlist, err := LoadAllowlist(projectDir)
+	if err != nil {
+		return nil, err
+	}
+
+	return allowlist.Filter(findings), nil
+}
+
+// checkInstallLocation warns when install_location is empty but payload/
+// has files that would have nowhere to go on nupkg/SMB-style backends.
+func checkInstallLocation(projectDir, installLocation string) []Finding {
+	if installLocation != "" {
+		return nil
+	}
+
+	payloadDir := filepath.Join(projectDir, "payload")
+	entries, err := os.ReadDir(payloadDir)
+	if err != nil || len(entries) == 0 {
+		return nil
+	}
+
+	return []Finding{{
+		ID:       "GP010",
+		Severity: SeverityWarning,
+		File:     "build-info.yaml",
+		Message:  "install_location is empty but payload/ is not",
+	}}
+}
+
+// checkScripts runs the PowerShell checks over every .ps1 under scripts/.
+func checkScripts(projectDir string) ([]Finding, error) {
+	scriptsDir := filepath.Join(projectDir, "scripts")
+	entries, err := os.ReadDir(scriptsDir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading %s: %w", scriptsDir, err)
+	}
+
+	var findings []Finding
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".ps1" {
+			continue
+		}
+		scriptFindings, err := checkPowerShellScript(filepath.Join(scriptsDir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		findings = append(findings, scriptFindings...)
+	}
+	return findings, nil
+}
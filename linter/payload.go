@@ -0,0 +1,69 @@
+package linter
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// maxPayloadFileSize is the default size threshold past which a payload
+// file triggers a warning, intended to catch accidentally bundled build
+// artifacts rather than enforce a hard limit.
+const maxPayloadFileSize = 200 * 1024 * 1024 // 200 MiB
+
+// forbiddenExtensions lists files that should never ship in a payload.
+var forbiddenExtensions = map[string]bool{
+	".pdb":      true,
+	".DS_Store": true,
+	"Thumbs.db": true,
+}
+
+// checkPayload walks payloadDir, warning on oversized files and erroring on
+// forbidden ones.
+func checkPayload(projectDir string) ([]Finding, error) {
+	payloadDir := filepath.Join(projectDir, "payload")
+	if _, err := os.Stat(payloadDir); os.IsNotExist(err) {
+		return nil, nil
+	}
+
+	var findings []Finding
+	err := filepath.Walk(payloadDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+
+		relPath, relErr := filepath.Rel(projectDir, path)
+		if relErr != nil {
+			relPath = path
+		}
+
+		name := info.Name()
+		if forbiddenExtensions[name] || forbiddenExtensions[filepath.Ext(name)] {
+			findings = append(findings, Finding{
+				ID:       "GP009",
+				Severity: SeverityError,
+				File:     relPath,
+				Message:  fmt.Sprintf("payload file %q should not be shipped", name),
+			})
+		}
+
+		if info.Size() > maxPayloadFileSize {
+			findings = append(findings, Finding{
+				ID:       "GP008",
+				Severity: SeverityWarning,
+				File:     relPath,
+				Message:  fmt.Sprintf("payload file is %d bytes, over the %d byte threshold", info.Size(), int64(maxPayloadFileSize)),
+			})
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error walking payload directory: %w", err)
+	}
+
+	return findings, nil
+}
@@ -0,0 +1,93 @@
+package linter
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+var utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+
+// checkPowerShellScript flags a .ps1 file that is missing the
+// $ErrorActionPreference = 'Stop' guard, or that carries a UTF-8 BOM or CRLF
+// line endings that don't match the rest of the repo's scripts.
+func checkPowerShellScript(path string) ([]Finding, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading %s: %w", path, err)
+	}
+
+	var findings []Finding
+	relName := filepath.Base(path)
+
+	if bytes.HasPrefix(data, utf8BOM) {
+		findings = append(findings, Finding{
+			ID:       "GP006",
+			Severity: SeverityWarning,
+			File:     relName,
+			Line:     1,
+			Message:  "file starts with a UTF-8 BOM",
+		})
+	}
+
+	if bytes.Contains(data, []byte("\r\n")) {
+		findings = append(findings, Finding{
+			ID:       "GP007",
+			Severity: SeverityWarning,
+			File:     relName,
+			Message:  "file contains CRLF line endings",
+		})
+	}
+
+	hasErrorActionPreference := false
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		if strings.Contains(scanner.Text(), "$ErrorActionPreference") && strings.Contains(scanner.Text(), "'Stop'") {
+			hasErrorActionPreference = true
+			break
+		}
+	}
+
+	if !hasErrorActionPreference {
+		findings = append(findings, Finding{
+			ID:       "GP005",
+			Severity: SeverityWarning,
+			File:     relName,
+			Line:     1,
+			Message:  "script is missing $ErrorActionPreference = 'Stop'",
+		})
+	}
+
+	return findings, nil
+}
+
+// checkPostInstallConflict warns when build-info.yaml's postinstall_action
+// would trigger a shutdown/logout that a hand-written postinstall.ps1
+// already performs itself.
+func checkPostInstallConflict(projectDir, postInstallAction string) ([]Finding, error) {
+	if postInstallAction == "" || strings.EqualFold(postInstallAction, "none") {
+		return nil, nil
+	}
+
+	path := filepath.Join(projectDir, "scripts", "postinstall.ps1")
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading %s: %w", path, err)
+	}
+
+	if strings.Contains(string(data), "shutdown") {
+		return []Finding{{
+			ID:       "GP004",
+			Severity: SeverityError,
+			File:     "scripts/postinstall.ps1",
+			Message:  fmt.Sprintf("postinstall_action %q will also run shutdown, and scripts/postinstall.ps1 already calls shutdown itself", postInstallAction),
+		}}, nil
+	}
+	return nil, nil
+}
@@ -0,0 +1,49 @@
+package linter
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// semVerPattern matches SemVer 2.0.0 (major.minor.patch with optional
+// -prerelease and +build metadata).
+var semVerPattern = regexp.MustCompile(`^\d+\.\d+\.\d+(-[0-9A-Za-z-.]+)?(\+[0-9A-Za-z-.]+)?$`)
+
+// nuGetFourPartPattern matches NuGet's legacy 4-part version scheme.
+var nuGetFourPartPattern = regexp.MustCompile(`^\d+\.\d+\.\d+\.\d+$`)
+
+// checkVersion validates product.version against SemVer or NuGet's 4-part
+// scheme, and warns about leading zeros, which some package managers treat
+// as distinct from the un-padded equivalent.
+func checkVersion(version string) []Finding {
+	var findings []Finding
+
+	if !semVerPattern.MatchString(version) && !nuGetFourPartPattern.MatchString(version) {
+		findings = append(findings, Finding{
+			ID:       "GP001",
+			Severity: SeverityError,
+			File:     "build-info.yaml",
+			Message:  fmt.Sprintf("product.version %q is not valid SemVer or a NuGet 4-part version", version),
+		})
+		return findings
+	}
+
+	numericPart := version
+	if idx := strings.IndexAny(version, "-+"); idx != -1 {
+		numericPart = version[:idx]
+	}
+	for _, part := range strings.Split(numericPart, ".") {
+		if len(part) > 1 && part[0] == '0' {
+			findings = append(findings, Finding{
+				ID:       "GP002",
+				Severity: SeverityWarning,
+				File:     "build-info.yaml",
+				Message:  fmt.Sprintf("product.version %q has a leading zero in %q", version, part),
+			})
+			break
+		}
+	}
+
+	return findings
+}
@@ -0,0 +1,32 @@
+package linter
+
+import "testing"
+
+func TestCheckVersionValid(t *testing.T) {
+	for _, version := range []string{
+		"1.0.0",
+		"1.0.0-beta.1",
+		"1.0.0+build.5",
+		"1.2.3.4",
+	} {
+		if findings := checkVersion(version); len(findings) != 0 {
+			t.Errorf("checkVersion(%q) = %+v, want none", version, findings)
+		}
+	}
+}
+
+func TestCheckVersionInvalid(t *testing.T) {
+	for _, version := range []string{"", "1.0", "v1.0.0", "1.0.0.0.0"} {
+		findings := checkVersion(version)
+		if len(findings) != 1 || findings[0].ID != "GP001" {
+			t.Errorf("checkVersion(%q) = %+v, want a single GP001 finding", version, findings)
+		}
+	}
+}
+
+func TestCheckVersionLeadingZeroWarns(t *testing.T) {
+	findings := checkVersion("1.02.0")
+	if len(findings) != 1 || findings[0].ID != "GP002" || findings[0].Severity != SeverityWarning {
+		t.Errorf("checkVersion(\"1.02.0\") = %+v, want a single GP002 warning", findings)
+	}
+}
@@ -0,0 +1,91 @@
+// Package packager defines the build-info model and the Builder interface
+// that each supported package format implements.
+package packager
+
+import "fmt"
+
+// BuildInfo holds package build information parsed from YAML.
+type BuildInfo struct {
+	InstallLocation    string            `yaml:"install_location"`
+	PostInstallAction  string            `yaml:"postinstall_action"`
+	SigningCertificate string            `yaml:"signing_certificate,omitempty"`
+	Maintainer         string            `yaml:"maintainer,omitempty"`
+	License            string            `yaml:"license,omitempty"`
+	Homepage           string            `yaml:"homepage,omitempty"`
+	Arch               string            `yaml:"arch,omitempty"`
+	Depends            []string          `yaml:"depends,omitempty"`
+	Scripts            map[string]string `yaml:"scripts,omitempty"`
+	Targets            []Target          `yaml:"targets,omitempty"`
+	Product            struct {
+		Identifier  string `yaml:"identifier"`
+		Version     string `yaml:"version"`
+		Name        string `yaml:"name"`
+		Developer   string `yaml:"developer"`
+		Description string `yaml:"description,omitempty"`
+	} `yaml:"product"`
+}
+
+// Context carries everything a Builder needs to produce a package from a
+// single project directory: the parsed build-info.yaml, and the resolved
+// payload/scripts/build paths.
+type Context struct {
+	ProjectDir string
+	PayloadDir string
+	ScriptsDir string
+	BuildDir   string
+	BuildInfo  *BuildInfo
+	Verbose    bool
+
+	// SourceDateEpoch is the Unix timestamp backends use in place of build
+	// time when normalizing file timestamps, so that packaging the same
+	// source tree twice produces a byte-identical package.
+	SourceDateEpoch int64
+}
+
+// Builder produces a single installable package from a Context and, when a
+// signing certificate is configured, signs it using that format's native
+// signing mechanism.
+type Builder interface {
+	// Format returns the short name used to select this Builder via --format.
+	Format() string
+	// Build packages the project described by ctx and returns the path to
+	// the resulting package file.
+	Build(ctx *Context) (string, error)
+	// Sign signs the package at pkgPath using the certificate/key configured
+	// in ctx.BuildInfo.SigningCertificate. Implementations that sign as part
+	// of Build (e.g. because the underlying packaging library embeds
+	// signing) may treat this as a no-op.
+	Sign(ctx *Context, pkgPath string) error
+}
+
+// DefaultSourceDateEpoch is used when neither --source-date-epoch nor the
+// SOURCE_DATE_EPOCH environment variable is set: 1980-01-01T00:00:00Z, the
+// earliest timestamp the ZIP format can represent, chosen so builds are
+// reproducible by default.
+const DefaultSourceDateEpoch int64 = 315532800
+
+var registry = map[string]func() Builder{}
+
+// Register makes a Builder factory available under the given format name.
+// Backends call this from an init() function.
+func Register(format string, factory func() Builder) {
+	registry[format] = factory
+}
+
+// NewBuilder returns a fresh Builder for the given --format value.
+func NewBuilder(format string) (Builder, error) {
+	factory, ok := registry[format]
+	if !ok {
+		return nil, fmt.Errorf("unsupported package format: %s", format)
+	}
+	return factory(), nil
+}
+
+// Formats returns the list of registered format names, for usage/help text.
+func Formats() []string {
+	formats := make([]string, 0, len(registry))
+	for format := range registry {
+		formats = append(formats, format)
+	}
+	return formats
+}
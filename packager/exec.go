@@ -0,0 +1,16 @@
+package packager
+
+import (
+	"log"
+	"os"
+	"os/exec"
+)
+
+// runCommand executes shell commands with logging.
+func runCommand(command string, args ...string) error {
+	log.Printf("Running: %s %v", command, args)
+	cmd := exec.Command(command, args...)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
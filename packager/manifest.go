@@ -0,0 +1,106 @@
+package packager
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"gopkg.in/yaml.v2"
+)
+
+// ManifestEntry records one payload file's identity for reproducibility
+// checks: its path, size, content hash, and permission bits.
+type ManifestEntry struct {
+	Path   string `yaml:"path"`
+	Size   int64  `yaml:"size"`
+	SHA256 string `yaml:"sha256"`
+	Mode   uint32 `yaml:"mode"`
+}
+
+// Manifest is the content-addressed sidecar written alongside a built
+// package, letting downstream consumers tell a real payload change from
+// re-pack noise.
+type Manifest struct {
+	Files []ManifestEntry `yaml:"files"`
+}
+
+// ManifestPath returns the sidecar manifest path for a built package, e.g.
+// build/hello-1.0.0.nupkg -> build/hello-1.0.0.manifest.yaml.
+func ManifestPath(pkgPath string) string {
+	ext := filepath.Ext(pkgPath)
+	return pkgPath[:len(pkgPath)-len(ext)] + ".manifest.yaml"
+}
+
+// BuildManifest walks payloadDir in sorted order, hashing every file.
+func BuildManifest(payloadDir string) (*Manifest, error) {
+	var manifest Manifest
+
+	if _, err := os.Stat(payloadDir); os.IsNotExist(err) {
+		return &manifest, nil
+	}
+
+	err := filepath.Walk(payloadDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+
+		relPath, err := filepath.Rel(payloadDir, path)
+		if err != nil {
+			return err
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		sum := sha256.Sum256(data)
+
+		manifest.Files = append(manifest.Files, ManifestEntry{
+			Path:   filepath.ToSlash(relPath),
+			Size:   info.Size(),
+			SHA256: hex.EncodeToString(sum[:]),
+			Mode:   uint32(info.Mode().Perm()),
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error walking payload directory: %w", err)
+	}
+
+	sort.Slice(manifest.Files, func(i, j int) bool {
+		return manifest.Files[i].Path < manifest.Files[j].Path
+	})
+
+	return &manifest, nil
+}
+
+// WriteManifest serializes manifest as YAML to path.
+func WriteManifest(manifest *Manifest, path string) error {
+	data, err := yaml.Marshal(manifest)
+	if err != nil {
+		return fmt.Errorf("error encoding manifest: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("error writing manifest %s: %w", path, err)
+	}
+	return nil
+}
+
+// ReadManifest loads a manifest previously written by WriteManifest.
+func ReadManifest(path string) (*Manifest, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading manifest %s: %w", path, err)
+	}
+	var manifest Manifest
+	if err := yaml.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("error parsing manifest %s: %w", path, err)
+	}
+	return &manifest, nil
+}
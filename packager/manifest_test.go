@@ -0,0 +1,85 @@
+package packager
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestBuildManifestSortedAndHashed(t *testing.T) {
+	payloadDir := t.TempDir()
+	mustWriteFile(t, filepath.Join(payloadDir, "b.txt"), "b")
+	mustWriteFile(t, filepath.Join(payloadDir, "a", "c.txt"), "c")
+
+	manifest, err := BuildManifest(payloadDir)
+	if err != nil {
+		t.Fatalf("BuildManifest: %v", err)
+	}
+	if len(manifest.Files) != 2 {
+		t.Fatalf("len(Files) = %d, want 2: %+v", len(manifest.Files), manifest.Files)
+	}
+
+	// Sorted by path, independent of filesystem walk order.
+	if manifest.Files[0].Path != filepath.ToSlash(filepath.Join("a", "c.txt")) {
+		t.Errorf("Files[0].Path = %q, want a/c.txt", manifest.Files[0].Path)
+	}
+	if manifest.Files[1].Path != "b.txt" {
+		t.Errorf("Files[1].Path = %q, want b.txt", manifest.Files[1].Path)
+	}
+
+	for _, f := range manifest.Files {
+		if f.SHA256 == "" {
+			t.Errorf("Files entry %q has empty SHA256", f.Path)
+		}
+		if f.Size != 1 {
+			t.Errorf("Files entry %q has size %d, want 1", f.Path, f.Size)
+		}
+	}
+}
+
+func TestBuildManifestMissingPayloadDir(t *testing.T) {
+	manifest, err := BuildManifest(filepath.Join(t.TempDir(), "does-not-exist"))
+	if err != nil {
+		t.Fatalf("BuildManifest: %v", err)
+	}
+	if len(manifest.Files) != 0 {
+		t.Errorf("len(Files) = %d, want 0", len(manifest.Files))
+	}
+}
+
+func TestManifestWriteReadRoundTrip(t *testing.T) {
+	manifest := &Manifest{Files: []ManifestEntry{
+		{Path: "a.txt", Size: 1, SHA256: "deadbeef", Mode: 0644},
+	}}
+
+	path := filepath.Join(t.TempDir(), "pkg.manifest.yaml")
+	if err := WriteManifest(manifest, path); err != nil {
+		t.Fatalf("WriteManifest: %v", err)
+	}
+
+	got, err := ReadManifest(path)
+	if err != nil {
+		t.Fatalf("ReadManifest: %v", err)
+	}
+	if len(got.Files) != 1 || got.Files[0] != manifest.Files[0] {
+		t.Errorf("ReadManifest = %+v, want %+v", got.Files, manifest.Files)
+	}
+}
+
+func TestManifestPath(t *testing.T) {
+	got := ManifestPath(filepath.Join("build", "hello-1.0.0.nupkg"))
+	want := filepath.Join("build", "hello-1.0.0.manifest.yaml")
+	if got != want {
+		t.Errorf("ManifestPath() = %q, want %q", got, want)
+	}
+}
+
+func mustWriteFile(t *testing.T, path, contents string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatalf("MkdirAll: %v", err)
+	}
+	if err := os.WriteFile(path, []byte(contents), 0644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+}
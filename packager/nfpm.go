@@ -0,0 +1,204 @@
+package packager
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/goreleaser/nfpm/v2"
+	"github.com/goreleaser/nfpm/v2/files"
+
+	_ "github.com/goreleaser/nfpm/v2/apk"
+	_ "github.com/goreleaser/nfpm/v2/arch"
+	_ "github.com/goreleaser/nfpm/v2/deb"
+	_ "github.com/goreleaser/nfpm/v2/rpm"
+)
+
+func init() {
+	for _, format := range []string{"deb", "rpm", "apk", "archlinux"} {
+		format := format
+		Register(format, func() Builder { return &NfpmBuilder{format: format} })
+	}
+}
+
+// NfpmBuilder packages a project as a .deb, .rpm, .apk, or archlinux package
+// via nfpm, which embeds signing into Build rather than exposing a separate
+// signing step.
+type NfpmBuilder struct {
+	format string
+}
+
+// Format implements Builder.
+func (b *NfpmBuilder) Format() string { return b.format }
+
+// Build implements Builder.
+func (b *NfpmBuilder) Build(ctx *Context) (string, error) {
+	if err := CreateProjectDirectory(ctx.ProjectDir); err != nil {
+		return "", fmt.Errorf("error creating directories: %w", err)
+	}
+
+	info, err := b.buildInfoToNfpm(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	pkgr, err := nfpm.Get(b.format)
+	if err != nil {
+		return "", fmt.Errorf("unsupported nfpm format %q: %w", b.format, err)
+	}
+
+	if err := os.MkdirAll(ctx.BuildDir, os.ModePerm); err != nil {
+		return "", fmt.Errorf("failed to create build directory: %w", err)
+	}
+
+	pkgPath := filepath.Join(ctx.BuildDir, pkgr.ConventionalFileName(info))
+	out, err := os.Create(pkgPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to create %s: %w", pkgPath, err)
+	}
+	defer out.Close()
+
+	if err := pkgr.Package(info, out); err != nil {
+		return "", fmt.Errorf("failed to build %s package: %w", b.format, err)
+	}
+
+	log.Printf("Package created successfully: %s", pkgPath)
+	return pkgPath, nil
+}
+
+// Sign implements Builder. deb/rpm/apk packages are signed as part of Build,
+// via the key file set on nfpm.Info in buildInfoToNfpm, so there is nothing
+// further to do here. nfpm has no signing support for archlinux packages, so
+// a configured certificate can't be honored for that format.
+func (b *NfpmBuilder) Sign(ctx *Context, pkgPath string) error {
+	if ctx.BuildInfo.SigningCertificate == "" {
+		return nil
+	}
+	if b.format == "archlinux" {
+		return fmt.Errorf("signing_certificate is set but archlinux packages cannot be signed: nfpm has no archlinux signing support")
+	}
+	log.Printf("Signing for %s packages is configured via nfpm.Info during Build; skipping separate sign step.", b.format)
+	return nil
+}
+
+// buildInfoToNfpm translates a BuildInfo and its payload tree into an
+// nfpm.Info ready to hand to a Packager.
+func (b *NfpmBuilder) buildInfoToNfpm(ctx *Context) (*nfpm.Info, error) {
+	buildInfo := ctx.BuildInfo
+
+	// nfpm, unlike ParseVersion, accepts full SemVer (prerelease/build
+	// metadata), which is also what the linter and templated versions
+	// (e.g. version: "1.0.0+{{.Git.Commit}}") produce, so pass it through
+	// as-is rather than rejecting anything with non-numeric characters.
+	version := buildInfo.Product.Version
+
+	description := buildInfo.Product.Description
+	if description == "" {
+		description = fmt.Sprintf(
+			"%s version %s for %s by %s",
+			buildInfo.Product.Name, version,
+			buildInfo.Product.Identifier, buildInfo.Product.Developer,
+		)
+	}
+
+	contents, err := nfpmContents(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	info := &nfpm.Info{
+		Name:        buildInfo.Product.Name,
+		Arch:        buildInfo.Arch,
+		Platform:    "linux",
+		Version:     version,
+		Maintainer:  buildInfo.Maintainer,
+		Description: description,
+		Vendor:      buildInfo.Product.Developer,
+		Homepage:    buildInfo.Homepage,
+		License:     buildInfo.License,
+		Overridables: nfpm.Overridables{
+			Depends:  buildInfo.Depends,
+			Contents: contents,
+			Scripts: nfpm.Scripts{
+				PreInstall:  nfpmScriptPath(ctx, "preinstall"),
+				PostInstall: nfpmScriptPath(ctx, "postinstall"),
+				PreRemove:   nfpmScriptPath(ctx, "preremove"),
+				PostRemove:  nfpmScriptPath(ctx, "postremove"),
+			},
+		},
+	}
+
+	if buildInfo.SigningCertificate != "" && b.format != "archlinux" {
+		info.Deb.Signature.KeyFile = buildInfo.SigningCertificate
+		info.RPM.Signature.KeyFile = buildInfo.SigningCertificate
+		info.APK.Signature.KeyFile = buildInfo.SigningCertificate
+	}
+
+	if buildInfo.PostInstallAction != "" && buildInfo.PostInstallAction != "none" {
+		log.Printf("Warning: postinstall_action %q is not supported by the %s backend and will be ignored", buildInfo.PostInstallAction, b.format)
+	}
+	warnIfConventionalScriptIgnored(ctx, "preinstall.ps1")
+	warnIfConventionalScriptIgnored(ctx, "postinstall.ps1")
+
+	if err := info.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid %s package metadata: %w", b.format, err)
+	}
+
+	return info, nil
+}
+
+// nfpmScriptPath resolves buildInfo.Scripts[name] against ctx.ProjectDir, the
+// same way nfpmContents resolves payload files, so relative paths in
+// build-info.yaml work regardless of the caller's working directory.
+func nfpmScriptPath(ctx *Context, name string) string {
+	script := ctx.BuildInfo.Scripts[name]
+	if script == "" || filepath.IsAbs(script) {
+		return script
+	}
+	return filepath.Join(ctx.ProjectDir, script)
+}
+
+// warnIfConventionalScriptIgnored logs a warning when a project has the
+// nupkg-specific scripts/<name> file (e.g. preinstall.ps1) but the nfpm
+// backends don't consume it; they only read scripts from the generic
+// scripts: map in build-info.yaml.
+func warnIfConventionalScriptIgnored(ctx *Context, name string) {
+	path := filepath.Join(ctx.ScriptsDir, name)
+	if _, err := os.Stat(path); err == nil {
+		log.Printf("Warning: scripts/%s exists but is only used by the nupkg backend; add it to build-info.yaml's scripts: map to run it here", name)
+	}
+}
+
+// nfpmContents walks the payload directory and maps each file onto its
+// destination under install_location.
+func nfpmContents(ctx *Context) (files.Contents, error) {
+	var contents files.Contents
+
+	if _, err := os.Stat(ctx.PayloadDir); os.IsNotExist(err) {
+		return contents, nil
+	}
+
+	err := filepath.Walk(ctx.PayloadDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+		relPath, err := filepath.Rel(ctx.PayloadDir, path)
+		if err != nil {
+			return err
+		}
+		contents = append(contents, &files.Content{
+			Source:      path,
+			Destination: filepath.Join(ctx.BuildInfo.InstallLocation, relPath),
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error walking payload directory: %w", err)
+	}
+
+	return contents, nil
+}
@@ -0,0 +1,118 @@
+package packager
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func newNfpmTestContext(t *testing.T, buildInfo *BuildInfo) *Context {
+	t.Helper()
+	projectDir := t.TempDir()
+	mustWriteFile(t, filepath.Join(projectDir, "payload", "bin", "app.sh"), "#!/bin/sh\n")
+	return NewContextFromBuildInfo(projectDir, buildInfo, false)
+}
+
+func TestBuildInfoToNfpmAcceptsSemVerPrereleaseAndBuildMetadata(t *testing.T) {
+	for _, version := range []string{"1.0.0-rc1", "1.0.0+abc1234", "2.1.0-rc1"} {
+		buildInfo := &BuildInfo{Arch: "x86_64", InstallLocation: "/opt/app"}
+		buildInfo.Product.Name = "app"
+		buildInfo.Product.Version = version
+
+		builder := &NfpmBuilder{format: "deb"}
+		info, err := builder.buildInfoToNfpm(newNfpmTestContext(t, buildInfo))
+		if err != nil {
+			t.Fatalf("buildInfoToNfpm(version=%q): %v", version, err)
+		}
+		if info.Version != version {
+			t.Errorf("info.Version = %q, want unchanged %q", info.Version, version)
+		}
+	}
+}
+
+func TestBuildInfoToNfpmContentsUnderInstallLocation(t *testing.T) {
+	buildInfo := &BuildInfo{Arch: "x86_64", InstallLocation: "/opt/app"}
+	buildInfo.Product.Name = "app"
+	buildInfo.Product.Version = "1.0.0"
+
+	builder := &NfpmBuilder{format: "deb"}
+	info, err := builder.buildInfoToNfpm(newNfpmTestContext(t, buildInfo))
+	if err != nil {
+		t.Fatalf("buildInfoToNfpm: %v", err)
+	}
+
+	if len(info.Overridables.Contents) != 1 {
+		t.Fatalf("len(Contents) = %d, want 1: %+v", len(info.Overridables.Contents), info.Overridables.Contents)
+	}
+	want := filepath.Join("/opt/app", "bin", "app.sh")
+	if got := info.Overridables.Contents[0].Destination; got != want {
+		t.Errorf("Contents[0].Destination = %q, want %q", got, want)
+	}
+}
+
+func TestBuildInfoToNfpmWiresSigningCertificateExceptArchlinux(t *testing.T) {
+	buildInfo := &BuildInfo{Arch: "x86_64", InstallLocation: "/opt/app", SigningCertificate: "/tmp/key.gpg"}
+	buildInfo.Product.Name = "app"
+	buildInfo.Product.Version = "1.0.0"
+
+	deb := &NfpmBuilder{format: "deb"}
+	info, err := deb.buildInfoToNfpm(newNfpmTestContext(t, buildInfo))
+	if err != nil {
+		t.Fatalf("buildInfoToNfpm(deb): %v", err)
+	}
+	if info.Deb.Signature.KeyFile != "/tmp/key.gpg" {
+		t.Errorf("Deb.Signature.KeyFile = %q, want /tmp/key.gpg", info.Deb.Signature.KeyFile)
+	}
+	if info.RPM.Signature.KeyFile != "/tmp/key.gpg" {
+		t.Errorf("RPM.Signature.KeyFile = %q, want /tmp/key.gpg", info.RPM.Signature.KeyFile)
+	}
+	if info.APK.Signature.KeyFile != "/tmp/key.gpg" {
+		t.Errorf("APK.Signature.KeyFile = %q, want /tmp/key.gpg", info.APK.Signature.KeyFile)
+	}
+
+	arch := &NfpmBuilder{format: "archlinux"}
+	archInfo, err := arch.buildInfoToNfpm(newNfpmTestContext(t, buildInfo))
+	if err != nil {
+		t.Fatalf("buildInfoToNfpm(archlinux): %v", err)
+	}
+	if archInfo.Deb.Signature.KeyFile != "" {
+		t.Errorf("archlinux Deb.Signature.KeyFile = %q, want empty (no archlinux signing support)", archInfo.Deb.Signature.KeyFile)
+	}
+}
+
+func TestBuildInfoToNfpmResolvesScriptPathsAgainstProjectDir(t *testing.T) {
+	buildInfo := &BuildInfo{
+		Arch:            "x86_64",
+		InstallLocation: "/opt/app",
+		Scripts:         map[string]string{"postinstall": "scripts/nfpm-postinstall.sh"},
+	}
+	buildInfo.Product.Name = "app"
+	buildInfo.Product.Version = "1.0.0"
+
+	ctx := newNfpmTestContext(t, buildInfo)
+	builder := &NfpmBuilder{format: "deb"}
+	info, err := builder.buildInfoToNfpm(ctx)
+	if err != nil {
+		t.Fatalf("buildInfoToNfpm: %v", err)
+	}
+
+	want := filepath.Join(ctx.ProjectDir, "scripts", "nfpm-postinstall.sh")
+	if info.Overridables.Scripts.PostInstall != want {
+		t.Errorf("Scripts.PostInstall = %q, want %q (resolved against ProjectDir, not CWD)", info.Overridables.Scripts.PostInstall, want)
+	}
+}
+
+func TestNfpmSignArchlinuxErrorsWhenCertificateConfigured(t *testing.T) {
+	ctx := &Context{BuildInfo: &BuildInfo{SigningCertificate: "/tmp/key.gpg"}}
+	builder := &NfpmBuilder{format: "archlinux"}
+	if err := builder.Sign(ctx, "/tmp/pkg.pkg.tar.zst"); err == nil {
+		t.Error("Sign for archlinux with a certificate configured: want error, got nil")
+	}
+}
+
+func TestNfpmSignNoopWithoutCertificate(t *testing.T) {
+	ctx := &Context{BuildInfo: &BuildInfo{}}
+	builder := &NfpmBuilder{format: "deb"}
+	if err := builder.Sign(ctx, "/tmp/pkg.deb"); err != nil {
+		t.Errorf("Sign with no certificate configured: %v, want nil", err)
+	}
+}
@@ -0,0 +1,432 @@
+package packager
+
+import (
+	"encoding/xml"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+func init() {
+	Register("nupkg", func() Builder { return &NupkgBuilder{} })
+}
+
+// NupkgBuilder packages a project as a Chocolatey .nupkg, signing it with
+// SignTool when a certificate is configured.
+type NupkgBuilder struct{}
+
+// Format implements Builder.
+func (b *NupkgBuilder) Format() string { return "nupkg" }
+
+// nuspecPackage defines the structure of a .nuspec package.
+type nuspecPackage struct {
+	XMLName  xml.Name       `xml:"package"`
+	Metadata nuspecMetadata `xml:"metadata"`
+	Files    []nuspecFile   `xml:"files>file,omitempty"`
+}
+
+// nuspecMetadata stores the package metadata.
+type nuspecMetadata struct {
+	ID          string `xml:"id"`
+	Version     string `xml:"version"`
+	Authors     string `xml:"authors"`
+	Description string `xml:"description"`
+	Tags        string `xml:"tags,omitempty"`
+	Readme      string `xml:"readme,omitempty"`
+}
+
+// nuspecFile defines the source and target paths for files.
+type nuspecFile struct {
+	Src    string `xml:"src,attr"`
+	Target string `xml:"target,attr"`
+}
+
+// Build implements Builder.
+func (b *NupkgBuilder) Build(ctx *Context) (string, error) {
+	buildInfo := ctx.BuildInfo
+
+	if err := CreateProjectDirectory(ctx.ProjectDir); err != nil {
+		return "", fmt.Errorf("error creating directories: %w", err)
+	}
+	log.Println("Directories created successfully.")
+
+	if err := includePreinstallScript(ctx.ProjectDir); err != nil {
+		return "", fmt.Errorf("error including preinstall script: %w", err)
+	}
+
+	if err := createChocolateyInstallScript(buildInfo, ctx.ProjectDir); err != nil {
+		return "", fmt.Errorf("error generating chocolateyInstall.ps1: %w", err)
+	}
+
+	nuspecPath, err := generateNuspec(buildInfo, ctx.ProjectDir)
+	if err != nil {
+		return "", fmt.Errorf("error generating .nuspec: %w", err)
+	}
+	defer os.Remove(nuspecPath)
+	log.Printf(".nuspec generated at: %s", nuspecPath)
+
+	checkNuGet()
+
+	if err := runCommand("nuget", "pack", nuspecPath, "-OutputDirectory", ctx.BuildDir, "-NoPackageAnalysis"); err != nil {
+		return "", fmt.Errorf("error creating package: %w", err)
+	}
+
+	builtPkgName := NupkgFileName(buildInfo)
+	builtPkgPath := filepath.Join(ctx.BuildDir, builtPkgName)
+
+	// Find the generated package in case NuGet used the identifier in the name.
+	searchPattern := filepath.Join(ctx.BuildDir, buildInfo.Product.Identifier+"*.nupkg")
+	matches, _ := filepath.Glob(searchPattern)
+
+	var finalPkgPath string
+	if len(matches) > 0 {
+		log.Printf("Renaming package: %s to %s", matches[0], builtPkgPath)
+		if err := os.Rename(matches[0], builtPkgPath); err != nil {
+			return "", fmt.Errorf("failed to rename package: %w", err)
+		}
+		finalPkgPath = builtPkgPath
+	} else {
+		log.Printf("Package matching pattern not found, using: %s", builtPkgPath)
+		finalPkgPath = builtPkgPath
+	}
+
+	// Clean up the tools directory after packaging.
+	toolsDir := filepath.Join(ctx.ProjectDir, "tools")
+	if err := os.RemoveAll(toolsDir); err != nil {
+		log.Printf("Warning: Failed to remove tools directory: %v", err)
+	} else {
+		log.Println("Tools directory removed successfully.")
+	}
+
+	sourceDateEpoch := ctx.SourceDateEpoch
+	if sourceDateEpoch == 0 {
+		sourceDateEpoch = DefaultSourceDateEpoch
+	}
+	if err := RepackDeterministic(finalPkgPath, sourceDateEpoch); err != nil {
+		return "", fmt.Errorf("error normalizing package for reproducibility: %w", err)
+	}
+
+	manifest, err := BuildManifest(ctx.PayloadDir)
+	if err != nil {
+		return "", err
+	}
+	manifestPath := ManifestPath(finalPkgPath)
+	if err := WriteManifest(manifest, manifestPath); err != nil {
+		return "", err
+	}
+	log.Printf("Manifest written to: %s", manifestPath)
+
+	return finalPkgPath, nil
+}
+
+// NupkgFileName builds the output filename for a nupkg package, folding in
+// Arch when it's set so that multi-target builds sharing a version but
+// differing by arch (e.g. the per-arch targets: example) don't overwrite
+// each other in the build directory, mirroring how nfpm's
+// ConventionalFileName qualifies deb/rpm/apk/archlinux output by arch.
+// Callers that need to locate an already-built package (such as the tester
+// package) must use this same naming to find it.
+func NupkgFileName(buildInfo *BuildInfo) string {
+	name := buildInfo.Product.Name + "-" + buildInfo.Product.Version
+	if buildInfo.Arch != "" {
+		name += "-" + buildInfo.Arch
+	}
+	return name + ".nupkg"
+}
+
+// Sign implements Builder, signing the .nupkg using SignTool.
+func (b *NupkgBuilder) Sign(ctx *Context, pkgPath string) error {
+	if ctx.BuildInfo.SigningCertificate == "" {
+		log.Println("No signing certificate provided. Skipping signing.")
+		return nil
+	}
+	checkSignTool()
+	return signPackage(pkgPath, ctx.BuildInfo.SigningCertificate)
+}
+
+// createChocolateyInstallScript generates the chocolateyInstall.ps1 script.
+func createChocolateyInstallScript(buildInfo *BuildInfo, projectDir string) error {
+	scriptPath := filepath.Join(projectDir, "tools", "chocolateyInstall.ps1")
+	installLocation := normalizeInstallLocation(buildInfo.InstallLocation)
+
+	var scriptBuilder strings.Builder
+
+	// Build the PowerShell script with enhanced logging and validation
+	scriptBuilder.WriteString(fmt.Sprintf(`$ErrorActionPreference = 'Stop'
+
+$installLocation = '%s'
+if ($env:GORILLA_PKG_TEST_INSTALL_ROOT) {
+    # Set by "gorilla-pkg test --install-directory" to sandbox the
+    # install under a throwaway directory instead of the real system path.
+    $installLocation = $env:GORILLA_PKG_TEST_INSTALL_ROOT
+}
+
+# Ensure the install location exists (if defined)
+if ($installLocation -and $installLocation -ne '') {
+    try {
+        New-Item -ItemType Directory -Force -Path $installLocation | Out-Null
+        Write-Host "Created or verified install location: $installLocation"
+    } catch {
+        Write-Error "Failed to create or access: $installLocation"
+        exit 1
+    }
+} else {
+    Write-Host "No install location specified, skipping creation of directories."
+}
+
+# Copy files from the payload folder to the install location (if payload exists)
+$payloadPath = "$PSScriptRoot\..\payload"
+$payloadPath = [System.IO.Path]::GetFullPath($payloadPath)
+$payloadPath = $payloadPath.TrimEnd('\', '/')
+if (Test-Path $payloadPath) {
+    Write-Host "Payload path: $payloadPath"
+    Get-ChildItem -Path $payloadPath -Recurse | ForEach-Object {
+        $fullName = $_.FullName
+        $relativePath = $fullName.Substring($payloadPath.Length)
+        $relativePath = $relativePath.TrimStart('\', '/')
+        $destinationPath = Join-Path $installLocation $relativePath
+
+        if ($_.PSIsContainer) {
+            New-Item -ItemType Directory -Force -Path $destinationPath | Out-Null
+            Write-Host "Created directory: $destinationPath"
+        } else {
+            Copy-Item -Path $fullName -Destination $destinationPath -Force
+            Write-Host "Copied: $($fullName) -> $destinationPath"
+
+            # Validate if the file was copied successfully
+            if (-not (Test-Path -Path $destinationPath)) {
+                Write-Error "Failed to copy: $($fullName)"
+                exit 1
+            }
+        }
+    }
+} else {
+    Write-Host "No payload folder found. Proceeding with script-only installation."
+}
+`, installLocation))
+
+	// Handle post-install action if provided
+	if action := strings.ToLower(buildInfo.PostInstallAction); action != "" {
+		scriptBuilder.WriteString("\n# Executing post-install action\n")
+		switch action {
+		case "logout":
+			scriptBuilder.WriteString("Write-Host 'Logging out...'\nshutdown /l\n")
+		case "restart":
+			scriptBuilder.WriteString("Write-Host 'Restarting system...'\nshutdown /r /t 0\n")
+		case "none":
+			scriptBuilder.WriteString("Write-Host 'No post-install action required.'\n")
+		default:
+			return fmt.Errorf("unsupported post-install action: %s", action)
+		}
+	}
+
+	// Append custom post-install script if available
+	postInstallScriptPath := filepath.Join(projectDir, "scripts", "postinstall.ps1")
+	if _, err := os.Stat(postInstallScriptPath); err == nil {
+		scriptBuilder.WriteString("\n# Post-install script contents\n")
+		postInstallContent, err := os.ReadFile(postInstallScriptPath)
+		if err != nil {
+			return fmt.Errorf("failed to read postinstall.ps1: %w", err)
+		}
+		scriptBuilder.WriteString(string(postInstallContent))
+	}
+
+	// Write the PowerShell script to the tools directory
+	if err := os.MkdirAll(filepath.Dir(scriptPath), os.ModePerm); err != nil {
+		return fmt.Errorf("failed to create tools directory: %w", err)
+	}
+
+	if err := os.WriteFile(scriptPath, []byte(scriptBuilder.String()), 0644); err != nil {
+		return fmt.Errorf("failed to write chocolateyInstall.ps1: %w", err)
+	}
+	return nil
+}
+
+// normalizeInstallLocation ensures the install location path is properly formatted.
+func normalizeInstallLocation(path string) string {
+	// Replace forward slashes with backslashes
+	path = strings.ReplaceAll(path, "/", `\`)
+	// Remove any trailing backslashes
+	path = strings.TrimRight(path, `\`)
+	return path
+}
+
+// includePreinstallScript copies preinstall.ps1 to tools\chocolateyBeforeModify.ps1 if it exists.
+func includePreinstallScript(projectDir string) error {
+	preinstallSrcPath := filepath.Join(projectDir, "scripts", "preinstall.ps1")
+	preinstallDstPath := filepath.Join(projectDir, "tools", "chocolateyBeforeModify.ps1")
+
+	if _, err := os.Stat(preinstallSrcPath); err == nil {
+		// Ensure the tools directory exists
+		if err := os.MkdirAll(filepath.Dir(preinstallDstPath), os.ModePerm); err != nil {
+			return fmt.Errorf("failed to create tools directory: %w", err)
+		}
+		// Copy the preinstall.ps1 to tools\chocolateyBeforeModify.ps1
+		if err := CopyFile(preinstallSrcPath, preinstallDstPath); err != nil {
+			return fmt.Errorf("failed to copy preinstall.ps1 to chocolateyBeforeModify.ps1: %w", err)
+		}
+	}
+	return nil
+}
+
+// handlePostInstallScript manages the postinstall.ps1 file.
+func handlePostInstallScript(action, projectDir string) error {
+	postInstallPath := filepath.Join(projectDir, "scripts", "postinstall.ps1")
+	var command string
+
+	// Determine the command based on the action
+	switch action {
+	case "logout":
+		command = "shutdown /l\n"
+	case "restart":
+		command = "shutdown /r /t 0\n"
+	case "none":
+		log.Println("No post-install action required.")
+		return nil // No further action needed
+	default:
+		return fmt.Errorf("unknown post-install action: %s", action)
+	}
+
+	// Check if postinstall.ps1 exists and handle appropriately
+	var file *os.File
+	if _, err := os.Stat(postInstallPath); os.IsNotExist(err) {
+		// Create a new postinstall.ps1 file
+		log.Printf("Creating new postinstall.ps1: %s", postInstallPath)
+		if err := os.MkdirAll(filepath.Dir(postInstallPath), os.ModePerm); err != nil {
+			return fmt.Errorf("failed to create scripts directory: %v", err)
+		}
+		file, err = os.Create(postInstallPath)
+		if err != nil {
+			return fmt.Errorf("failed to create postinstall.ps1: %v", err)
+		}
+	} else {
+		// Append to the existing postinstall.ps1 file
+		log.Printf("Appending to existing postinstall.ps1: %s", postInstallPath)
+		file, err = os.OpenFile(postInstallPath, os.O_APPEND|os.O_WRONLY, os.ModeAppend)
+		if err != nil {
+			return fmt.Errorf("failed to open postinstall.ps1: %v", err)
+		}
+	}
+	defer file.Close()
+
+	// Write or append the command
+	if _, err := file.WriteString(command); err != nil {
+		return fmt.Errorf("failed to write to postinstall.ps1: %v", err)
+	}
+
+	log.Printf("Post-install command added: %s", command)
+	return nil
+}
+
+// generateNuspec builds the .nuspec file with proper payload handling.
+func generateNuspec(buildInfo *BuildInfo, projectDir string) (string, error) {
+	nuspecPath := filepath.Join(projectDir, buildInfo.Product.Name+".nuspec")
+
+	// Set the package description or use a default one.
+	description := buildInfo.Product.Description
+	if description == "" {
+		description = fmt.Sprintf(
+			"%s version %s for %s by %s",
+			buildInfo.Product.Name, buildInfo.Product.Version,
+			buildInfo.Product.Identifier, buildInfo.Product.Developer,
+		)
+	}
+
+	// Define the structure of the .nuspec package.
+	nuspec := nuspecPackage{
+		Metadata: nuspecMetadata{
+			ID:          buildInfo.Product.Identifier,
+			Version:     buildInfo.Product.Version,
+			Authors:     buildInfo.Product.Developer,
+			Description: description,
+			Tags:        "admin",
+		},
+	}
+
+	// Include all files from the /payload folder in the .nuspec with correct paths.
+	payloadPath := filepath.Join(projectDir, "payload")
+	if _, err := os.Stat(payloadPath); !os.IsNotExist(err) {
+		err := filepath.Walk(payloadPath, func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			if !info.IsDir() {
+				// Make the path relative to the project directory.
+				relPath, _ := filepath.Rel(projectDir, path)
+				nuspec.Files = append(nuspec.Files, nuspecFile{
+					Src:    relPath,
+					Target: relPath,
+				})
+			}
+			return nil
+		})
+		if err != nil {
+			return "", fmt.Errorf("error walking payload directory: %w", err)
+		}
+	}
+
+	// Always include the chocolateyInstall.ps1 script in the package.
+	nuspec.Files = append(nuspec.Files, nuspecFile{
+		Src:    filepath.Join("tools", "chocolateyInstall.ps1"),
+		Target: filepath.Join("tools", "chocolateyInstall.ps1"),
+	})
+
+	// Include preinstall and postinstall scripts if they exist.
+	addScriptToNuspec(&nuspec, projectDir, "preinstall.ps1", "chocolateyBeforeModify.ps1")
+	addScriptToNuspec(&nuspec, projectDir, "postinstall.ps1", "postinstall.ps1")
+
+	// Create the .nuspec file.
+	file, err := os.Create(nuspecPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to create .nuspec file: %w", err)
+	}
+	defer file.Close()
+
+	// Encode the .nuspec structure into the file as XML.
+	encoder := xml.NewEncoder(file)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(nuspec); err != nil {
+		return "", fmt.Errorf("failed to encode .nuspec: %w", err)
+	}
+
+	return nuspecPath, nil
+}
+
+// addScriptToNuspec adds a script file to the nuspec if it exists on disk.
+func addScriptToNuspec(nuspec *nuspecPackage, projectDir, scriptName, target string) {
+	scriptPath := filepath.Join(projectDir, "scripts", scriptName)
+	if _, err := os.Stat(scriptPath); !os.IsNotExist(err) {
+		nuspec.Files = append(nuspec.Files, nuspecFile{
+			Src:    filepath.Join("scripts", scriptName),
+			Target: filepath.Join("tools", target),
+		})
+	}
+}
+
+// signPackage signs the .nupkg using SignTool.
+func signPackage(nupkgFile, certificate string) error {
+	log.Printf("Signing package: %s with certificate: %s", nupkgFile, certificate)
+	return runCommand(
+		"signtool", "sign", "/n", certificate,
+		"/fd", "SHA256", "/tr", "http://timestamp.digicert.com",
+		"/td", "SHA256", nupkgFile,
+	)
+}
+
+// checkNuGet verifies that nuget is installed and on PATH.
+func checkNuGet() {
+	if err := runCommand("nuget", "locals", "all", "-list"); err != nil {
+		log.Fatalf(`NuGet is not installed or not in PATH.
+You can install it via Chocolatey:
+  choco install nuget.commandline`)
+	}
+}
+
+// checkSignTool verifies that signtool is installed and on PATH.
+func checkSignTool() {
+	if err := runCommand("signtool", "-?"); err != nil {
+		log.Fatalf("SignTool is not installed or not available: %v", err)
+	}
+}
@@ -0,0 +1,34 @@
+package packager
+
+import "testing"
+
+func TestNupkgFileNameOmitsArchWhenUnset(t *testing.T) {
+	buildInfo := &BuildInfo{}
+	buildInfo.Product.Name = "app"
+	buildInfo.Product.Version = "1.0.0"
+
+	if got, want := NupkgFileName(buildInfo), "app-1.0.0.nupkg"; got != want {
+		t.Errorf("NupkgFileName = %q, want %q", got, want)
+	}
+}
+
+func TestNupkgFileNameDisambiguatesByArch(t *testing.T) {
+	x86 := &BuildInfo{Arch: "x86_64"}
+	x86.Product.Name = "app"
+	x86.Product.Version = "1.0.0"
+
+	arm := &BuildInfo{Arch: "arm64"}
+	arm.Product.Name = "app"
+	arm.Product.Version = "1.0.0"
+
+	gotX86, gotArm := NupkgFileName(x86), NupkgFileName(arm)
+	if gotX86 == gotArm {
+		t.Fatalf("NupkgFileName produced the same name for different arches: %q", gotX86)
+	}
+	if want := "app-1.0.0-x86_64.nupkg"; gotX86 != want {
+		t.Errorf("NupkgFileName(x86_64) = %q, want %q", gotX86, want)
+	}
+	if want := "app-1.0.0-arm64.nupkg"; gotArm != want {
+		t.Errorf("NupkgFileName(arm64) = %q, want %q", gotArm, want)
+	}
+}
@@ -0,0 +1,123 @@
+package packager
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// NormalizePath ensures paths use consistent separators across platforms.
+func NormalizePath(input string) string {
+	return filepath.FromSlash(strings.ReplaceAll(input, "\\", "/"))
+}
+
+// VerifyProjectStructure checks that either the payload or scripts folder exists.
+func VerifyProjectStructure(projectDir string) error {
+	payloadPath := filepath.Join(projectDir, "payload")
+	scriptsPath := filepath.Join(projectDir, "scripts")
+
+	// Check if at least one of the two required paths exists.
+	if _, err := os.Stat(payloadPath); os.IsNotExist(err) {
+		if _, err := os.Stat(scriptsPath); os.IsNotExist(err) {
+			return fmt.Errorf("either 'payload' or 'scripts' directory must exist in the project directory")
+		}
+	}
+
+	// Ensure the build-info.yaml file exists.
+	buildInfoPath := filepath.Join(projectDir, "build-info.yaml")
+	if _, err := os.Stat(buildInfoPath); os.IsNotExist(err) {
+		return fmt.Errorf("'build-info.yaml' file is missing in the project directory")
+	}
+
+	return nil
+}
+
+// ReadBuildInfo loads and parses build-info.yaml from the given directory.
+func ReadBuildInfo(projectDir string) (*BuildInfo, error) {
+	path := filepath.Join(projectDir, "build-info.yaml")
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading build-info.yaml: %w", err)
+	}
+
+	var buildInfo BuildInfo
+	if err := yaml.Unmarshal(data, &buildInfo); err != nil {
+		return nil, fmt.Errorf("error parsing YAML: %w", err)
+	}
+
+	return &buildInfo, nil
+}
+
+// ParseVersion converts version strings to a normalized format.
+func ParseVersion(versionStr string) (string, error) {
+	parts := strings.Split(versionStr, ".")
+	var numericParts []string
+
+	// Convert all parts to strings to preserve the original input, ensuring they're valid numbers.
+	for _, part := range parts {
+		if _, err := strconv.Atoi(part); err != nil {
+			return "", fmt.Errorf("invalid version part: %q is not a number", part)
+		}
+		numericParts = append(numericParts, part)
+	}
+
+	// Join the parts back together to form the version string.
+	return strings.Join(numericParts, "."), nil
+}
+
+// CreateProjectDirectory ensures necessary project directories exist.
+func CreateProjectDirectory(projectDir string) error {
+	subDirs := []string{
+		"payload",
+		"scripts",
+		"build",
+	}
+
+	for _, subDir := range subDirs {
+		fullPath := filepath.Join(projectDir, subDir)
+		if err := os.MkdirAll(fullPath, os.ModePerm); err != nil {
+			return fmt.Errorf("failed to create directory %s: %w", fullPath, err)
+		}
+	}
+	return nil
+}
+
+// CopyFile copies a file from src to dst.
+func CopyFile(src, dst string) error {
+	input, err := os.ReadFile(src)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(dst, input, 0644); err != nil {
+		return err
+	}
+	return nil
+}
+
+// NewContext builds a Context for projectDir, resolving the standard
+// payload/scripts/build subdirectories and loading build-info.yaml.
+func NewContext(projectDir string, verbose bool) (*Context, error) {
+	buildInfo, err := ReadBuildInfo(projectDir)
+	if err != nil {
+		return nil, err
+	}
+	return NewContextFromBuildInfo(projectDir, buildInfo, verbose), nil
+}
+
+// NewContextFromBuildInfo builds a Context for projectDir from an
+// already-parsed BuildInfo, for callers (such as multi-target builds) that
+// need to resolve/expand build-info.yaml themselves before building.
+func NewContextFromBuildInfo(projectDir string, buildInfo *BuildInfo, verbose bool) *Context {
+	return &Context{
+		ProjectDir: projectDir,
+		PayloadDir: filepath.Join(projectDir, "payload"),
+		ScriptsDir: filepath.Join(projectDir, "scripts"),
+		BuildDir:   filepath.Join(projectDir, "build"),
+		BuildInfo:  buildInfo,
+		Verbose:    verbose,
+	}
+}
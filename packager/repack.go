@@ -0,0 +1,86 @@
+package packager
+
+import (
+	"archive/zip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// RepackDeterministic rewrites the zip-based package at pkgPath so that
+// entries are in sorted order and every timestamp is pinned to
+// sourceDateEpoch, so packaging the same source tree twice produces a
+// byte-identical file regardless of the order NuGet happened to add entries
+// in or when the build ran.
+func RepackDeterministic(pkgPath string, sourceDateEpoch int64) error {
+	zr, err := zip.OpenReader(pkgPath)
+	if err != nil {
+		return fmt.Errorf("error opening %s for repacking: %w", pkgPath, err)
+	}
+	defer zr.Close()
+
+	entries := make([]*zip.File, len(zr.File))
+	copy(entries, zr.File)
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Name < entries[j].Name
+	})
+
+	tmp, err := os.CreateTemp(filepath.Dir(pkgPath), ".gorilla-pkg-repack-*.tmp")
+	if err != nil {
+		return fmt.Errorf("error creating temp file for repacking: %w", err)
+	}
+	tmpPath := tmp.Name()
+	defer os.Remove(tmpPath)
+
+	modTime := time.Unix(sourceDateEpoch, 0).UTC()
+	zw := zip.NewWriter(tmp)
+
+	for _, entry := range entries {
+		header := entry.FileHeader
+		header.Modified = modTime
+		// zip.Writer.CreateHeader appends a fresh extended-timestamp Extra
+		// field for the pinned Modified time but never strips whatever
+		// Extra bytes (including the original, unpinned timestamp) the
+		// source entry already carried, which would otherwise leak through
+		// and make the repacked archive depend on the original build's
+		// timestamps after all.
+		header.Extra = nil
+
+		w, err := zw.CreateHeader(&header)
+		if err != nil {
+			zw.Close()
+			tmp.Close()
+			return fmt.Errorf("error writing %s into repacked archive: %w", entry.Name, err)
+		}
+
+		r, err := entry.Open()
+		if err != nil {
+			zw.Close()
+			tmp.Close()
+			return fmt.Errorf("error reading %s from %s: %w", entry.Name, pkgPath, err)
+		}
+		_, err = io.Copy(w, r)
+		r.Close()
+		if err != nil {
+			zw.Close()
+			tmp.Close()
+			return fmt.Errorf("error copying %s into repacked archive: %w", entry.Name, err)
+		}
+	}
+
+	if err := zw.Close(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("error finalizing repacked archive: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("error closing repacked archive: %w", err)
+	}
+
+	if err := os.Rename(tmpPath, pkgPath); err != nil {
+		return fmt.Errorf("error moving repacked archive into place: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,96 @@
+package packager
+
+import (
+	"archive/zip"
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// writeZip builds a zip file at path from name->contents, writing entries in
+// the given order and with distinct (non-reproducible) timestamps, the way
+// two separate build runs might.
+func writeZip(t *testing.T, path string, order []string, contents map[string]string) {
+	t.Helper()
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	defer f.Close()
+
+	zw := zip.NewWriter(f)
+	for i, name := range order {
+		header := &zip.FileHeader{Name: name, Method: zip.Deflate}
+		header.Modified = time.Unix(int64(1000+i*60), 0)
+		w, err := zw.CreateHeader(header)
+		if err != nil {
+			t.Fatalf("CreateHeader: %v", err)
+		}
+		if _, err := w.Write([]byte(contents[name])); err != nil {
+			t.Fatalf("Write: %v", err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatalf("zip Close: %v", err)
+	}
+}
+
+func TestRepackDeterministicIsOrderAndTimeIndependent(t *testing.T) {
+	contents := map[string]string{
+		"payload/a.txt": "a",
+		"payload/b.txt": "b",
+	}
+
+	dir := t.TempDir()
+	pkgA := filepath.Join(dir, "a.nupkg")
+	pkgB := filepath.Join(dir, "b.nupkg")
+	writeZip(t, pkgA, []string{"payload/a.txt", "payload/b.txt"}, contents)
+	writeZip(t, pkgB, []string{"payload/b.txt", "payload/a.txt"}, contents)
+
+	const epoch = int64(315532800)
+	if err := RepackDeterministic(pkgA, epoch); err != nil {
+		t.Fatalf("RepackDeterministic(a): %v", err)
+	}
+	if err := RepackDeterministic(pkgB, epoch); err != nil {
+		t.Fatalf("RepackDeterministic(b): %v", err)
+	}
+
+	dataA, err := os.ReadFile(pkgA)
+	if err != nil {
+		t.Fatalf("ReadFile(a): %v", err)
+	}
+	dataB, err := os.ReadFile(pkgB)
+	if err != nil {
+		t.Fatalf("ReadFile(b): %v", err)
+	}
+
+	if !bytes.Equal(dataA, dataB) {
+		t.Errorf("repacked archives differ despite identical contents in different entry order")
+	}
+}
+
+func TestRepackDeterministicPinsTimestamps(t *testing.T) {
+	dir := t.TempDir()
+	pkgPath := filepath.Join(dir, "pkg.nupkg")
+	writeZip(t, pkgPath, []string{"payload/a.txt"}, map[string]string{"payload/a.txt": "a"})
+
+	const epoch = int64(315532800)
+	if err := RepackDeterministic(pkgPath, epoch); err != nil {
+		t.Fatalf("RepackDeterministic: %v", err)
+	}
+
+	zr, err := zip.OpenReader(pkgPath)
+	if err != nil {
+		t.Fatalf("OpenReader: %v", err)
+	}
+	defer zr.Close()
+
+	want := time.Unix(epoch, 0).UTC()
+	for _, f := range zr.File {
+		if !f.Modified.Equal(want) {
+			t.Errorf("entry %s has Modified %v, want %v", f.Name, f.Modified, want)
+		}
+	}
+}
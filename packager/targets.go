@@ -0,0 +1,206 @@
+package packager
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// Target overrides a subset of top-level BuildInfo fields for one named
+// build variant, letting a single project directory produce dev/staging/
+// prod or per-arch builds without duplicating build-info.yaml.
+type Target struct {
+	Name               string `yaml:"name"`
+	Version            string `yaml:"version,omitempty"`
+	Arch               string `yaml:"arch,omitempty"`
+	InstallLocation    string `yaml:"install_location,omitempty"`
+	SigningCertificate string `yaml:"signing_certificate,omitempty"`
+}
+
+// ResolvedTarget pairs a target name (empty when build-info.yaml has no
+// targets: list) with the BuildInfo to build it from.
+type ResolvedTarget struct {
+	Name      string
+	BuildInfo *BuildInfo
+}
+
+// ResolveTargets applies each selected target's overrides on top of a copy
+// of buildInfo. names may be empty or contain "all" to select every target;
+// otherwise only the named targets are built. When buildInfo has no
+// targets: list, names is ignored and buildInfo itself is returned as the
+// single build.
+func ResolveTargets(buildInfo *BuildInfo, names []string) ([]ResolvedTarget, error) {
+	if len(buildInfo.Targets) == 0 {
+		return []ResolvedTarget{{BuildInfo: buildInfo}}, nil
+	}
+
+	selected := buildInfo.Targets
+	if len(names) > 0 && !(len(names) == 1 && names[0] == "all") {
+		selected = nil
+		for _, name := range names {
+			target, ok := findTarget(buildInfo.Targets, name)
+			if !ok {
+				return nil, fmt.Errorf("unknown target: %s", name)
+			}
+			selected = append(selected, target)
+		}
+	}
+
+	resolved := make([]ResolvedTarget, 0, len(selected))
+	for _, target := range selected {
+		resolved = append(resolved, ResolvedTarget{
+			Name:      target.Name,
+			BuildInfo: applyTarget(buildInfo, target),
+		})
+	}
+	return resolved, nil
+}
+
+func findTarget(targets []Target, name string) (Target, bool) {
+	for _, t := range targets {
+		if t.Name == name {
+			return t, true
+		}
+	}
+	return Target{}, false
+}
+
+// applyTarget returns a copy of buildInfo with target's non-empty fields
+// overlaid on top.
+func applyTarget(buildInfo *BuildInfo, target Target) *BuildInfo {
+	result := *buildInfo
+	result.Targets = nil
+	result.Depends = append([]string(nil), buildInfo.Depends...)
+
+	if target.Version != "" {
+		result.Product.Version = target.Version
+	}
+	if target.Arch != "" {
+		result.Arch = target.Arch
+	}
+	if target.InstallLocation != "" {
+		result.InstallLocation = target.InstallLocation
+	}
+	if target.SigningCertificate != "" {
+		result.SigningCertificate = target.SigningCertificate
+	}
+
+	return &result
+}
+
+// TemplateVars is the fixed set of variables available to
+// text/template expansions inside build-info.yaml string values.
+type TemplateVars struct {
+	Env    map[string]string
+	Git    GitInfo
+	Date   string
+	Target TargetVars
+}
+
+// GitInfo carries the project's current commit and tag, each empty when
+// projectDir isn't a git repository or the lookup fails.
+type GitInfo struct {
+	Commit string
+	Tag    string
+}
+
+// TargetVars exposes the target currently being built to its own templates.
+type TargetVars struct {
+	Name string
+}
+
+// NewTemplateVars builds the TemplateVars for building targetName out of
+// projectDir.
+func NewTemplateVars(projectDir, targetName string) TemplateVars {
+	return TemplateVars{
+		Env:    environMap(),
+		Git:    gitInfoFor(projectDir),
+		Date:   time.Now().UTC().Format("2006-01-02"),
+		Target: TargetVars{Name: targetName},
+	}
+}
+
+func environMap() map[string]string {
+	env := map[string]string{}
+	for _, kv := range os.Environ() {
+		if idx := strings.IndexByte(kv, '='); idx >= 0 {
+			env[kv[:idx]] = kv[idx+1:]
+		}
+	}
+	return env
+}
+
+func gitInfoFor(projectDir string) GitInfo {
+	return GitInfo{
+		Commit: runGit(projectDir, "rev-parse", "HEAD"),
+		Tag:    runGit(projectDir, "describe", "--tags", "--abbrev=0"),
+	}
+}
+
+// runGit shells out to git -C projectDir <args>, returning "" on any error
+// (e.g. projectDir isn't a git repository) rather than failing the build.
+func runGit(projectDir string, args ...string) string {
+	cmd := exec.Command("git", append([]string{"-C", projectDir}, args...)...)
+	out, err := cmd.Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// ExpandBuildInfo runs text/template expansion over every templatable
+// string field of buildInfo in place, using vars.
+func ExpandBuildInfo(buildInfo *BuildInfo, vars TemplateVars) error {
+	fields := []*string{
+		&buildInfo.InstallLocation,
+		&buildInfo.PostInstallAction,
+		&buildInfo.SigningCertificate,
+		&buildInfo.Maintainer,
+		&buildInfo.License,
+		&buildInfo.Homepage,
+		&buildInfo.Arch,
+		&buildInfo.Product.Identifier,
+		&buildInfo.Product.Version,
+		&buildInfo.Product.Name,
+		&buildInfo.Product.Developer,
+		&buildInfo.Product.Description,
+	}
+
+	for _, field := range fields {
+		expanded, err := expandTemplate(*field, vars)
+		if err != nil {
+			return err
+		}
+		*field = expanded
+	}
+
+	for i, dep := range buildInfo.Depends {
+		expanded, err := expandTemplate(dep, vars)
+		if err != nil {
+			return err
+		}
+		buildInfo.Depends[i] = expanded
+	}
+
+	return nil
+}
+
+func expandTemplate(value string, vars TemplateVars) (string, error) {
+	if !strings.Contains(value, "{{") {
+		return value, nil
+	}
+
+	tmpl, err := template.New("build-info").Parse(value)
+	if err != nil {
+		return "", fmt.Errorf("error parsing template %q: %w", value, err)
+	}
+
+	var out strings.Builder
+	if err := tmpl.Execute(&out, vars); err != nil {
+		return "", fmt.Errorf("error expanding template %q: %w", value, err)
+	}
+	return out.String(), nil
+}
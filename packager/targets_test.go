@@ -0,0 +1,127 @@
+package packager
+
+import "testing"
+
+func TestResolveTargetsNoTargetsReturnsBuildInfoAsIs(t *testing.T) {
+	buildInfo := &BuildInfo{Arch: "x86_64"}
+
+	resolved, err := ResolveTargets(buildInfo, nil)
+	if err != nil {
+		t.Fatalf("ResolveTargets: %v", err)
+	}
+	if len(resolved) != 1 || resolved[0].BuildInfo != buildInfo || resolved[0].Name != "" {
+		t.Errorf("resolved = %+v, want the single unnamed original BuildInfo", resolved)
+	}
+}
+
+func TestResolveTargetsAllSelectsEveryTarget(t *testing.T) {
+	buildInfo := &BuildInfo{Targets: []Target{{Name: "dev"}, {Name: "prod"}}}
+
+	resolved, err := ResolveTargets(buildInfo, nil)
+	if err != nil {
+		t.Fatalf("ResolveTargets: %v", err)
+	}
+	if len(resolved) != 2 {
+		t.Fatalf("len(resolved) = %d, want 2", len(resolved))
+	}
+	if resolved[0].Name != "dev" || resolved[1].Name != "prod" {
+		t.Errorf("resolved names = %q, %q, want dev, prod", resolved[0].Name, resolved[1].Name)
+	}
+}
+
+func TestResolveTargetsByName(t *testing.T) {
+	buildInfo := &BuildInfo{Targets: []Target{{Name: "dev"}, {Name: "prod"}}}
+
+	resolved, err := ResolveTargets(buildInfo, []string{"prod"})
+	if err != nil {
+		t.Fatalf("ResolveTargets: %v", err)
+	}
+	if len(resolved) != 1 || resolved[0].Name != "prod" {
+		t.Errorf("resolved = %+v, want only prod", resolved)
+	}
+}
+
+func TestResolveTargetsUnknownNameErrors(t *testing.T) {
+	buildInfo := &BuildInfo{Targets: []Target{{Name: "dev"}}}
+
+	if _, err := ResolveTargets(buildInfo, []string{"staging"}); err == nil {
+		t.Error("ResolveTargets with an unknown target name: want error, got nil")
+	}
+}
+
+func TestApplyTargetOverridesOnlySetFields(t *testing.T) {
+	buildInfo := &BuildInfo{
+		Arch:            "x86_64",
+		InstallLocation: "/opt/app",
+		Depends:         []string{"libc"},
+	}
+	buildInfo.Product.Version = "1.0.0"
+
+	result := applyTarget(buildInfo, Target{Name: "arm", Arch: "arm64"})
+
+	if result.Arch != "arm64" {
+		t.Errorf("Arch = %q, want arm64", result.Arch)
+	}
+	if result.InstallLocation != "/opt/app" {
+		t.Errorf("InstallLocation = %q, want unchanged /opt/app", result.InstallLocation)
+	}
+	if result.Product.Version != "1.0.0" {
+		t.Errorf("Product.Version = %q, want unchanged 1.0.0", result.Product.Version)
+	}
+}
+
+func TestApplyTargetDoesNotShareDependsSliceAcrossTargets(t *testing.T) {
+	buildInfo := &BuildInfo{Depends: []string{"libc"}}
+
+	dev := applyTarget(buildInfo, Target{Name: "dev"})
+	prod := applyTarget(buildInfo, Target{Name: "prod"})
+
+	dev.Depends[0] = "libc-dev"
+
+	if prod.Depends[0] != "libc" {
+		t.Errorf("prod.Depends[0] = %q, want unchanged %q after mutating dev's copy", prod.Depends[0], "libc")
+	}
+	if buildInfo.Depends[0] != "libc" {
+		t.Errorf("buildInfo.Depends[0] = %q, want unchanged %q after mutating dev's copy", buildInfo.Depends[0], "libc")
+	}
+}
+
+func TestExpandBuildInfoSubstitutesTargetName(t *testing.T) {
+	buildInfo := &BuildInfo{InstallLocation: `C:\Program Files\{{.Target.Name}}`}
+	buildInfo.Product.Name = "app-{{.Target.Name}}"
+	buildInfo.Depends = []string{"dep-{{.Target.Name}}"}
+
+	vars := TemplateVars{Target: TargetVars{Name: "dev"}}
+	if err := ExpandBuildInfo(buildInfo, vars); err != nil {
+		t.Fatalf("ExpandBuildInfo: %v", err)
+	}
+
+	if buildInfo.InstallLocation != `C:\Program Files\dev` {
+		t.Errorf("InstallLocation = %q, want %q", buildInfo.InstallLocation, `C:\Program Files\dev`)
+	}
+	if buildInfo.Product.Name != "app-dev" {
+		t.Errorf("Product.Name = %q, want app-dev", buildInfo.Product.Name)
+	}
+	if buildInfo.Depends[0] != "dep-dev" {
+		t.Errorf("Depends[0] = %q, want dep-dev", buildInfo.Depends[0])
+	}
+}
+
+func TestExpandBuildInfoLeavesPlainFieldsUntouched(t *testing.T) {
+	buildInfo := &BuildInfo{Arch: "x86_64"}
+
+	if err := ExpandBuildInfo(buildInfo, TemplateVars{}); err != nil {
+		t.Fatalf("ExpandBuildInfo: %v", err)
+	}
+	if buildInfo.Arch != "x86_64" {
+		t.Errorf("Arch = %q, want unchanged x86_64", buildInfo.Arch)
+	}
+}
+
+func TestExpandBuildInfoInvalidTemplateErrors(t *testing.T) {
+	buildInfo := &BuildInfo{Arch: "{{.Broken"}
+
+	if err := ExpandBuildInfo(buildInfo, TemplateVars{}); err == nil {
+		t.Error("ExpandBuildInfo with an unterminated template action: want error, got nil")
+	}
+}
@@ -0,0 +1,74 @@
+package packager
+
+import (
+	"archive/zip"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Verify recomputes payload file hashes from the built package at pkgPath
+// and diffs them against the sidecar manifest written at build time. It
+// returns a human-readable list of mismatches (empty when everything
+// matches) and an error only on I/O failure.
+func Verify(pkgPath string, manifest *Manifest) ([]string, error) {
+	zr, err := zip.OpenReader(pkgPath)
+	if err != nil {
+		return nil, fmt.Errorf("error opening %s: %w", pkgPath, err)
+	}
+	defer zr.Close()
+
+	actual := map[string]ManifestEntry{}
+	for _, f := range zr.File {
+		if f.FileInfo().IsDir() {
+			continue
+		}
+		relPath, ok := strings.CutPrefix(f.Name, "payload/")
+		if !ok {
+			continue
+		}
+
+		rc, err := f.Open()
+		if err != nil {
+			return nil, fmt.Errorf("error reading %s from %s: %w", f.Name, pkgPath, err)
+		}
+		h := sha256.New()
+		size, err := io.Copy(h, rc)
+		rc.Close()
+		if err != nil {
+			return nil, fmt.Errorf("error hashing %s from %s: %w", f.Name, pkgPath, err)
+		}
+
+		actual[relPath] = ManifestEntry{
+			Path:   relPath,
+			Size:   size,
+			SHA256: hex.EncodeToString(h.Sum(nil)),
+		}
+	}
+
+	var diffs []string
+	seen := map[string]bool{}
+	for _, expected := range manifest.Files {
+		seen[expected.Path] = true
+		got, ok := actual[expected.Path]
+		switch {
+		case !ok:
+			diffs = append(diffs, fmt.Sprintf("missing from package: %s", expected.Path))
+		case got.SHA256 != expected.SHA256:
+			diffs = append(diffs, fmt.Sprintf("content changed: %s (manifest sha256 %s, package sha256 %s)",
+				expected.Path, expected.SHA256, got.SHA256))
+		case got.Size != expected.Size:
+			diffs = append(diffs, fmt.Sprintf("size changed: %s (manifest %d bytes, package %d bytes)",
+				expected.Path, expected.Size, got.Size))
+		}
+	}
+	for path := range actual {
+		if !seen[path] {
+			diffs = append(diffs, fmt.Sprintf("unexpected file in package: %s", path))
+		}
+	}
+
+	return diffs, nil
+}
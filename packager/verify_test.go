@@ -0,0 +1,76 @@
+package packager
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestVerifyMatchesCleanPackage(t *testing.T) {
+	dir := t.TempDir()
+	pkgPath := filepath.Join(dir, "pkg.nupkg")
+	writeZip(t, pkgPath, []string{"payload/a.txt"}, map[string]string{"payload/a.txt": "a"})
+
+	payloadDir := filepath.Join(dir, "payload")
+	mustWriteFile(t, filepath.Join(payloadDir, "a.txt"), "a")
+	manifest, err := BuildManifest(payloadDir)
+	if err != nil {
+		t.Fatalf("BuildManifest: %v", err)
+	}
+
+	diffs, err := Verify(pkgPath, manifest)
+	if err != nil {
+		t.Fatalf("Verify: %v", err)
+	}
+	if len(diffs) != 0 {
+		t.Errorf("diffs = %v, want none", diffs)
+	}
+}
+
+func TestVerifyDetectsContentChangeAndMissingAndExtraFiles(t *testing.T) {
+	dir := t.TempDir()
+	pkgPath := filepath.Join(dir, "pkg.nupkg")
+	writeZip(t, pkgPath, []string{"payload/a.txt", "payload/extra.txt"}, map[string]string{
+		"payload/a.txt":     "changed",
+		"payload/extra.txt": "unexpected",
+	})
+
+	originalManifest, err := BuildManifest(singleFilePayloadDir(t, "a.txt", "original"))
+	if err != nil {
+		t.Fatalf("BuildManifest: %v", err)
+	}
+	manifest := &Manifest{Files: append(originalManifest.Files, ManifestEntry{
+		Path: "missing.txt", Size: 1, SHA256: "deadbeef",
+	})}
+
+	diffs, err := Verify(pkgPath, manifest)
+	if err != nil {
+		t.Fatalf("Verify: %v", err)
+	}
+
+	for _, want := range []string{
+		"content changed: a.txt",
+		"missing from package: missing.txt",
+		"unexpected file in package: extra.txt",
+	} {
+		if !diffsContain(diffs, want) {
+			t.Errorf("diffs = %v, want one containing %q", diffs, want)
+		}
+	}
+}
+
+func singleFilePayloadDir(t *testing.T, name, contents string) string {
+	t.Helper()
+	dir := t.TempDir()
+	mustWriteFile(t, filepath.Join(dir, name), contents)
+	return dir
+}
+
+func diffsContain(diffs []string, substr string) bool {
+	for _, diff := range diffs {
+		if strings.Contains(diff, substr) {
+			return true
+		}
+	}
+	return false
+}
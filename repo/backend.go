@@ -0,0 +1,140 @@
+package repo
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+)
+
+// Backend uploads a blob of data to a relative path within a repo, and reads
+// one back so catalog manifests can be fetched, updated, and republished.
+type Backend interface {
+	// Upload writes data to relativePath, replacing any existing blob.
+	Upload(relativePath string, data []byte) error
+	// Fetch reads the blob at relativePath. It returns os.ErrNotExist
+	// (wrapped) when the blob does not exist yet, which callers treat as an
+	// empty starting point.
+	Fetch(relativePath string) ([]byte, error)
+}
+
+// NewBackend builds the Backend configured by cfg.
+func NewBackend(cfg *RepoConfig) (Backend, error) {
+	switch cfg.Backend {
+	case "local", "smb":
+		// SMB shares are expected to already be mounted at cfg.Path, so the
+		// local backend handles both cases identically.
+		return &localBackend{root: cfg.Path}, nil
+	case "s3":
+		return newS3Backend(cfg)
+	case "http":
+		return &httpBackend{
+			baseURL:  cfg.URL,
+			username: cfg.Username,
+			password: cfg.Password,
+		}, nil
+	default:
+		return nil, fmt.Errorf("unsupported repo backend: %q", cfg.Backend)
+	}
+}
+
+// localBackend writes to a local directory (or a pre-mounted SMB share)
+// using a temp-file-then-rename to keep writes atomic.
+type localBackend struct {
+	root string
+}
+
+func (b *localBackend) Upload(relativePath string, data []byte) error {
+	dest := filepath.Join(b.root, relativePath)
+	if err := os.MkdirAll(filepath.Dir(dest), os.ModePerm); err != nil {
+		return fmt.Errorf("failed to create %s: %w", filepath.Dir(dest), err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(dest), ".gorilla-pkg-*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create temp file for %s: %w", dest, err)
+	}
+	tmpPath := tmp.Name()
+	defer os.Remove(tmpPath)
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write %s: %w", tmpPath, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to close %s: %w", tmpPath, err)
+	}
+
+	if err := os.Rename(tmpPath, dest); err != nil {
+		return fmt.Errorf("failed to move %s into place at %s: %w", tmpPath, dest, err)
+	}
+	return nil
+}
+
+func (b *localBackend) Fetch(relativePath string) ([]byte, error) {
+	data, err := os.ReadFile(filepath.Join(b.root, relativePath))
+	if err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// httpBackend uploads via HTTP PUT against baseURL.
+type httpBackend struct {
+	baseURL  string
+	username string
+	password string
+}
+
+func (b *httpBackend) Upload(relativePath string, data []byte) error {
+	req, err := http.NewRequest(http.MethodPut, b.url(relativePath), bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	b.authenticate(req)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("PUT %s: %w", req.URL, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("PUT %s: unexpected status %s", req.URL, resp.Status)
+	}
+	return nil
+}
+
+func (b *httpBackend) Fetch(relativePath string) ([]byte, error) {
+	req, err := http.NewRequest(http.MethodGet, b.url(relativePath), nil)
+	if err != nil {
+		return nil, err
+	}
+	b.authenticate(req)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("GET %s: %w", req.URL, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("%s: %w", relativePath, os.ErrNotExist)
+	}
+	if resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("GET %s: unexpected status %s", req.URL, resp.Status)
+	}
+	return io.ReadAll(resp.Body)
+}
+
+func (b *httpBackend) url(relativePath string) string {
+	return b.baseURL + "/" + relativePath
+}
+
+func (b *httpBackend) authenticate(req *http.Request) {
+	if b.username != "" {
+		req.SetBasicAuth(b.username, b.password)
+	}
+}
@@ -0,0 +1,105 @@
+package repo
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// CatalogEntry describes one published package version as the Gorilla
+// client expects to find it in a catalog manifest.
+type CatalogEntry struct {
+	Identifier            string `yaml:"identifier"`
+	Version               string `yaml:"version"`
+	SHA256                string `yaml:"sha256"`
+	Size                  int64  `yaml:"size"`
+	InstallerItemLocation string `yaml:"installer_item_location"`
+	PostInstallAction     string `yaml:"postinstall_action,omitempty"`
+}
+
+// Catalog is the parsed form of a catalogs/<name>.yaml manifest.
+type Catalog struct {
+	Packages []CatalogEntry `yaml:"packages"`
+}
+
+// parseCatalog parses raw catalog YAML, treating empty input as an empty
+// catalog (the case for a catalog that has never been published to).
+func parseCatalog(data []byte) (*Catalog, error) {
+	var catalog Catalog
+	if len(data) == 0 {
+		return &catalog, nil
+	}
+	if err := yaml.Unmarshal(data, &catalog); err != nil {
+		return nil, err
+	}
+	return &catalog, nil
+}
+
+// marshal serializes the catalog back to YAML.
+func (c *Catalog) marshal() ([]byte, error) {
+	return yaml.Marshal(c)
+}
+
+// upsert replaces any existing entry for entry.Identifier+entry.Version and
+// appends otherwise, then prunes superseded versions of the same identifier
+// past keep (keep <= 0 means keep everything).
+func (c *Catalog) upsert(entry CatalogEntry, keep int) {
+	filtered := c.Packages[:0]
+	for _, existing := range c.Packages {
+		if existing.Identifier == entry.Identifier && existing.Version == entry.Version {
+			continue
+		}
+		filtered = append(filtered, existing)
+	}
+	c.Packages = append(filtered, entry)
+
+	if keep <= 0 {
+		return
+	}
+
+	byIdentifier := map[string][]CatalogEntry{}
+	var order []string
+	for _, e := range c.Packages {
+		if _, ok := byIdentifier[e.Identifier]; !ok {
+			order = append(order, e.Identifier)
+		}
+		byIdentifier[e.Identifier] = append(byIdentifier[e.Identifier], e)
+	}
+
+	var pruned []CatalogEntry
+	for _, identifier := range order {
+		versions := byIdentifier[identifier]
+		sort.Slice(versions, func(i, j int) bool {
+			return compareVersions(versions[i].Version, versions[j].Version) > 0
+		})
+		if len(versions) > keep {
+			versions = versions[:keep]
+		}
+		pruned = append(pruned, versions...)
+	}
+	c.Packages = pruned
+}
+
+// compareVersions compares two dotted numeric version strings, returning a
+// negative, zero, or positive number as a < b, a == b, or a > b. Non-numeric
+// parts sort before numeric ones rather than failing the comparison.
+func compareVersions(a, b string) int {
+	aParts := strings.Split(a, ".")
+	bParts := strings.Split(b, ".")
+
+	for i := 0; i < len(aParts) || i < len(bParts); i++ {
+		var aNum, bNum int
+		if i < len(aParts) {
+			aNum, _ = strconv.Atoi(aParts[i])
+		}
+		if i < len(bParts) {
+			bNum, _ = strconv.Atoi(bParts[i])
+		}
+		if aNum != bNum {
+			return aNum - bNum
+		}
+	}
+	return 0
+}
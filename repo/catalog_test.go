@@ -0,0 +1,81 @@
+package repo
+
+import "testing"
+
+func TestCompareVersions(t *testing.T) {
+	cases := []struct {
+		a, b string
+		want int
+	}{
+		{"1.0.0", "1.0.0", 0},
+		{"1.0.1", "1.0.0", 1},
+		{"1.0.0", "1.0.1", -1},
+		{"2.0", "1.9.9", 1},
+		{"1.2", "1.2.0", 0},
+		{"1.10.0", "1.9.0", 1},
+	}
+
+	for _, c := range cases {
+		if got := compareVersions(c.a, c.b); sign(got) != sign(c.want) {
+			t.Errorf("compareVersions(%q, %q) = %d, want sign %d", c.a, c.b, got, c.want)
+		}
+	}
+}
+
+func sign(n int) int {
+	switch {
+	case n < 0:
+		return -1
+	case n > 0:
+		return 1
+	default:
+		return 0
+	}
+}
+
+func TestCatalogUpsertReplacesMatchingVersion(t *testing.T) {
+	c := &Catalog{Packages: []CatalogEntry{
+		{Identifier: "com.example.app", Version: "1.0.0", SHA256: "old"},
+	}}
+
+	c.upsert(CatalogEntry{Identifier: "com.example.app", Version: "1.0.0", SHA256: "new"}, 0)
+
+	if len(c.Packages) != 1 {
+		t.Fatalf("len(Packages) = %d, want 1", len(c.Packages))
+	}
+	if c.Packages[0].SHA256 != "new" {
+		t.Errorf("SHA256 = %q, want %q", c.Packages[0].SHA256, "new")
+	}
+}
+
+func TestCatalogUpsertKeepPrunesOldestVersions(t *testing.T) {
+	c := &Catalog{}
+	for _, v := range []string{"1.0.0", "1.1.0", "1.2.0"} {
+		c.upsert(CatalogEntry{Identifier: "com.example.app", Version: v}, 2)
+	}
+
+	if len(c.Packages) != 2 {
+		t.Fatalf("len(Packages) = %d, want 2", len(c.Packages))
+	}
+	versions := map[string]bool{}
+	for _, e := range c.Packages {
+		versions[e.Version] = true
+	}
+	if versions["1.0.0"] {
+		t.Errorf("expected 1.0.0 to be pruned, Packages = %+v", c.Packages)
+	}
+	if !versions["1.1.0"] || !versions["1.2.0"] {
+		t.Errorf("expected 1.1.0 and 1.2.0 to survive, Packages = %+v", c.Packages)
+	}
+}
+
+func TestCatalogUpsertKeepIsPerIdentifier(t *testing.T) {
+	c := &Catalog{}
+	c.upsert(CatalogEntry{Identifier: "com.example.a", Version: "1.0.0"}, 1)
+	c.upsert(CatalogEntry{Identifier: "com.example.a", Version: "2.0.0"}, 1)
+	c.upsert(CatalogEntry{Identifier: "com.example.b", Version: "1.0.0"}, 1)
+
+	if len(c.Packages) != 2 {
+		t.Fatalf("len(Packages) = %d, want 2 (one survivor per identifier): %+v", len(c.Packages), c.Packages)
+	}
+}
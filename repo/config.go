@@ -0,0 +1,76 @@
+package repo
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v2"
+)
+
+// Config is the top-level ~/.gorilla-pkg.yaml document. It carries repo
+// credentials so that build-info.yaml stays portable across machines.
+type Config struct {
+	Repo RepoConfig `yaml:"repo"`
+}
+
+// RepoConfig describes where and how to publish built packages and catalogs.
+type RepoConfig struct {
+	// Backend selects the upload mechanism: "local", "smb", "s3", or "http".
+	Backend string `yaml:"backend"`
+
+	// Path is the destination root for the "local" and "smb" backends. For
+	// "smb" the share is expected to already be mounted at this path.
+	Path string `yaml:"path,omitempty"`
+
+	// URL is the base endpoint for the "http" backend; packages and catalogs
+	// are uploaded via PUT to URL joined with their relative repo path.
+	URL string `yaml:"url,omitempty"`
+
+	// Bucket, Region, AccessKey, and SecretKey configure the "s3" backend.
+	Bucket    string `yaml:"bucket,omitempty"`
+	Region    string `yaml:"region,omitempty"`
+	AccessKey string `yaml:"access_key,omitempty"`
+	SecretKey string `yaml:"secret_key,omitempty"`
+
+	// Username and Password authenticate the "smb" and "http" backends.
+	Username string `yaml:"username,omitempty"`
+	Password string `yaml:"password,omitempty"`
+
+	// SigningCertificate, when set, is used to produce a detached signature
+	// alongside each published catalog manifest.
+	SigningCertificate string `yaml:"signing_certificate,omitempty"`
+}
+
+// DefaultConfigPath returns ~/.gorilla-pkg.yaml for the current user.
+func DefaultConfigPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("error resolving home directory: %w", err)
+	}
+	return filepath.Join(home, ".gorilla-pkg.yaml"), nil
+}
+
+// LoadConfig reads and parses the gorilla-pkg repo config. If path is empty,
+// DefaultConfigPath is used.
+func LoadConfig(path string) (*Config, error) {
+	if path == "" {
+		var err error
+		path, err = DefaultConfigPath()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading %s: %w", path, err)
+	}
+
+	var cfg Config
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("error parsing %s: %w", path, err)
+	}
+
+	return &cfg, nil
+}
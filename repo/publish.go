@@ -0,0 +1,137 @@
+// Package repo publishes built packages to a Gorilla/Munki-style catalog
+// repo: it uploads the package blob to a configurable backend and updates
+// the relevant catalogs/*.yaml manifests to point at it.
+package repo
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+
+	"github.com/rodchristiansen/gorilla-pkg/packager"
+)
+
+// PublishOptions controls a single publish run.
+type PublishOptions struct {
+	// Catalogs lists the catalog names (catalogs/<name>.yaml) to update.
+	Catalogs []string
+	// Keep bounds how many versions of the same identifier are retained in
+	// each catalog after the new entry is added. Zero means keep all.
+	Keep int
+}
+
+// Publish uploads pkgPath to the backend configured by cfg and records it in
+// every catalog named in opts.Catalogs.
+func Publish(pkgPath string, buildInfo *packager.BuildInfo, cfg *RepoConfig, opts PublishOptions) error {
+	backend, err := NewBackend(cfg)
+	if err != nil {
+		return err
+	}
+
+	data, err := os.ReadFile(pkgPath)
+	if err != nil {
+		return fmt.Errorf("error reading %s: %w", pkgPath, err)
+	}
+
+	sum := sha256.Sum256(data)
+	sha256Hex := hex.EncodeToString(sum[:])
+
+	// Backend-relative keys are always forward-slash, independent of the
+	// host OS, since they're stored in the catalog and used verbatim by
+	// Gorilla clients on any platform.
+	pkgRelPath := path.Join("pkgs", filepath.Base(pkgPath))
+	log.Printf("Uploading %s to %s", pkgPath, pkgRelPath)
+	if err := backend.Upload(pkgRelPath, data); err != nil {
+		return fmt.Errorf("error uploading package: %w", err)
+	}
+
+	entry := CatalogEntry{
+		Identifier:            buildInfo.Product.Identifier,
+		Version:               buildInfo.Product.Version,
+		SHA256:                sha256Hex,
+		Size:                  int64(len(data)),
+		InstallerItemLocation: pkgRelPath,
+		PostInstallAction:     buildInfo.PostInstallAction,
+	}
+
+	for _, name := range opts.Catalogs {
+		if err := publishToCatalog(backend, cfg, name, entry, opts.Keep); err != nil {
+			return fmt.Errorf("error updating catalog %q: %w", name, err)
+		}
+	}
+
+	return nil
+}
+
+func publishToCatalog(backend Backend, cfg *RepoConfig, name string, entry CatalogEntry, keep int) error {
+	relPath := path.Join("catalogs", name+".yaml")
+
+	existing, err := backend.Fetch(relPath)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			return fmt.Errorf("error fetching existing catalog: %w", err)
+		}
+		existing = nil
+	}
+
+	catalog, err := parseCatalog(existing)
+	if err != nil {
+		return fmt.Errorf("error parsing existing catalog: %w", err)
+	}
+
+	catalog.upsert(entry, keep)
+
+	data, err := catalog.marshal()
+	if err != nil {
+		return fmt.Errorf("error encoding catalog: %w", err)
+	}
+
+	log.Printf("Publishing catalog update: %s", relPath)
+	if err := backend.Upload(relPath, data); err != nil {
+		return fmt.Errorf("error uploading catalog: %w", err)
+	}
+
+	if cfg.SigningCertificate != "" {
+		sig, err := signManifest(data, cfg.SigningCertificate)
+		if err != nil {
+			return fmt.Errorf("error signing catalog: %w", err)
+		}
+		if err := backend.Upload(relPath+".sig", sig); err != nil {
+			return fmt.Errorf("error uploading catalog signature: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// signManifest produces a detached signature for data using openssl and the
+// configured signing certificate/key.
+func signManifest(data []byte, certificate string) ([]byte, error) {
+	tmp, err := os.CreateTemp("", "gorilla-pkg-manifest-*.yaml")
+	if err != nil {
+		return nil, fmt.Errorf("failed to stage manifest for signing: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return nil, fmt.Errorf("failed to write staged manifest: %w", err)
+	}
+	tmp.Close()
+
+	sigPath := tmp.Name() + ".sig"
+	defer os.Remove(sigPath)
+
+	cmd := exec.Command("openssl", "dgst", "-sha256", "-sign", certificate, "-out", sigPath, tmp.Name())
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("openssl signing failed: %w", err)
+	}
+
+	return os.ReadFile(sigPath)
+}
@@ -0,0 +1,66 @@
+package repo
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/smithy-go"
+)
+
+// s3Backend uploads to an S3 bucket.
+type s3Backend struct {
+	client *s3.Client
+	bucket string
+}
+
+func newS3Backend(cfg *RepoConfig) (Backend, error) {
+	if cfg.Bucket == "" {
+		return nil, fmt.Errorf("s3 backend requires a bucket")
+	}
+
+	awsCfg := aws.Config{Region: cfg.Region}
+	if cfg.AccessKey != "" {
+		awsCfg.Credentials = credentials.NewStaticCredentialsProvider(cfg.AccessKey, cfg.SecretKey, "")
+	}
+
+	return &s3Backend{
+		client: s3.NewFromConfig(awsCfg),
+		bucket: cfg.Bucket,
+	}, nil
+}
+
+func (b *s3Backend) Upload(relativePath string, data []byte) error {
+	_, err := b.client.PutObject(context.Background(), &s3.PutObjectInput{
+		Bucket: aws.String(b.bucket),
+		Key:    aws.String(relativePath),
+		Body:   bytes.NewReader(data),
+	})
+	if err != nil {
+		return fmt.Errorf("s3 upload of %s to %s: %w", relativePath, b.bucket, err)
+	}
+	return nil
+}
+
+func (b *s3Backend) Fetch(relativePath string) ([]byte, error) {
+	out, err := b.client.GetObject(context.Background(), &s3.GetObjectInput{
+		Bucket: aws.String(b.bucket),
+		Key:    aws.String(relativePath),
+	})
+	if err != nil {
+		var apiErr smithy.APIError
+		if errors.As(err, &apiErr) && apiErr.ErrorCode() == "NoSuchKey" {
+			return nil, fmt.Errorf("%s: %w", relativePath, os.ErrNotExist)
+		}
+		return nil, fmt.Errorf("s3 fetch of %s from %s: %w", relativePath, b.bucket, err)
+	}
+	defer out.Body.Close()
+
+	return io.ReadAll(out.Body)
+}
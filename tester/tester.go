@@ -0,0 +1,207 @@
+// Package tester implements the install/uninstall verification flow used by
+// the `gorilla-pkg test` subcommand: install a built .nupkg into an isolated
+// Chocolatey lib, confirm its scripts and payload behaved, then uninstall it.
+package tester
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/rodchristiansen/gorilla-pkg/packager"
+)
+
+// Options controls how a built package is exercised.
+type Options struct {
+	// InstallDir is a local, isolated Chocolatey lib path used as the
+	// sandbox for the install. Ignored when VagrantDir is set.
+	InstallDir string
+	// VagrantDir, when set, points at a directory containing a Vagrantfile;
+	// the package is installed/uninstalled inside that box via `vagrant ssh`
+	// instead of on the host.
+	VagrantDir string
+	// Install runs `choco install` against the built package.
+	Install bool
+	// Uninstall runs `choco uninstall` after the install assertions pass.
+	Uninstall bool
+	// Parameters is passed through to choco as --package-parameters.
+	Parameters string
+}
+
+// Run installs (and optionally uninstalls) the package built for projectDir,
+// verifying its scripts ran cleanly and its payload landed under
+// install_location. It returns a non-nil error on the first failure.
+func Run(projectDir string, buildInfo *packager.BuildInfo, opts Options) error {
+	pkgPath, err := locateBuiltPackage(projectDir, buildInfo)
+	if err != nil {
+		return err
+	}
+	log.Printf("Testing package: %s", pkgPath)
+
+	if opts.Install {
+		if err := install(pkgPath, buildInfo, opts); err != nil {
+			return fmt.Errorf("install failed: %w", err)
+		}
+		if err := verifyPayloadInstalled(projectDir, buildInfo, opts); err != nil {
+			return fmt.Errorf("post-install verification failed: %w", err)
+		}
+	}
+
+	if opts.Uninstall {
+		if err := uninstall(buildInfo, opts); err != nil {
+			return fmt.Errorf("uninstall failed: %w", err)
+		}
+	}
+
+	log.Println("Package test completed successfully.")
+	return nil
+}
+
+// locateBuiltPackage finds the .nupkg produced by a prior `gorilla-pkg build`
+// for this project.
+func locateBuiltPackage(projectDir string, buildInfo *packager.BuildInfo) (string, error) {
+	pkgPath := filepath.Join(projectDir, "build", packager.NupkgFileName(buildInfo))
+	if _, err := os.Stat(pkgPath); err != nil {
+		return "", fmt.Errorf("built package not found at %s; run a build first: %w", pkgPath, err)
+	}
+	return pkgPath, nil
+}
+
+// install runs `choco install` against pkgPath, either on the host (scoped to
+// opts.InstallDir) or inside a Vagrant box.
+func install(pkgPath string, buildInfo *packager.BuildInfo, opts Options) error {
+	args := []string{"install", buildInfo.Product.Identifier,
+		"-s", filepath.Dir(pkgPath), "-y", "--no-progress"}
+	if opts.Parameters != "" {
+		args = append(args, "--package-parameters", opts.Parameters)
+	}
+	return runChoco(args, opts)
+}
+
+// uninstall runs `choco uninstall` for the package under test.
+func uninstall(buildInfo *packager.BuildInfo, opts Options) error {
+	args := []string{"uninstall", buildInfo.Product.Identifier, "-y", "--no-progress"}
+	if opts.Parameters != "" {
+		args = append(args, "--package-parameters", opts.Parameters)
+	}
+	return runChoco(args, opts)
+}
+
+// runChoco executes choco with args, either directly (scoping the
+// Chocolatey lib to opts.InstallDir via CHOCOLATEY_INSTALL) or, when
+// opts.VagrantDir is set, via `vagrant ssh` inside that box.
+func runChoco(args []string, opts Options) error {
+	if opts.VagrantDir != "" {
+		cmdline := "choco " + joinArgs(args)
+		log.Printf("Running inside vagrant box %s: %s", opts.VagrantDir, cmdline)
+		cmd := exec.Command("vagrant", "ssh", "-c", cmdline)
+		cmd.Dir = opts.VagrantDir
+		cmd.Stdout = os.Stdout
+		cmd.Stderr = os.Stderr
+		return cmd.Run()
+	}
+
+	log.Printf("Running: choco %v", args)
+	cmd := exec.Command("choco", args...)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if opts.InstallDir != "" {
+		if err := os.MkdirAll(opts.InstallDir, os.ModePerm); err != nil {
+			return fmt.Errorf("failed to create install directory %s: %w", opts.InstallDir, err)
+		}
+		// ChocolateyInstall scopes choco's own package-lib bookkeeping;
+		// GORILLA_PKG_TEST_INSTALL_ROOT is read by chocolateyInstall.ps1 to
+		// redirect the $installLocation it copies the payload into, so the
+		// whole install is sandboxed under opts.InstallDir rather than
+		// touching the real install_location on the host.
+		cmd.Env = append(os.Environ(),
+			"ChocolateyInstall="+opts.InstallDir,
+			"GORILLA_PKG_TEST_INSTALL_ROOT="+opts.InstallDir,
+		)
+	}
+	return cmd.Run()
+}
+
+// verifyPayloadInstalled asserts that every payload file was actually
+// written under the effective install root: opts.InstallDir when the
+// install was sandboxed, or buildInfo.InstallLocation otherwise. In vagrant
+// mode, the install happened inside the guest, so the check runs there too.
+func verifyPayloadInstalled(projectDir string, buildInfo *packager.BuildInfo, opts Options) error {
+	payloadDir := filepath.Join(projectDir, "payload")
+	if _, err := os.Stat(payloadDir); os.IsNotExist(err) {
+		return nil
+	}
+
+	installRoot := buildInfo.InstallLocation
+	if opts.InstallDir != "" {
+		installRoot = opts.InstallDir
+	}
+
+	var relPaths []string
+	err := filepath.Walk(payloadDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+		relPath, err := filepath.Rel(payloadDir, path)
+		if err != nil {
+			return err
+		}
+		relPaths = append(relPaths, relPath)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if opts.VagrantDir != "" {
+		return verifyPayloadInstalledInVagrant(opts.VagrantDir, installRoot, relPaths)
+	}
+
+	for _, relPath := range relPaths {
+		installedPath := filepath.Join(installRoot, relPath)
+		if _, err := os.Stat(installedPath); err != nil {
+			return fmt.Errorf("expected file missing after install: %s", installedPath)
+		}
+	}
+	return nil
+}
+
+// verifyPayloadInstalledInVagrant checks, via `vagrant ssh`, that every
+// payload file landed under installRoot inside the guest the install ran in.
+func verifyPayloadInstalledInVagrant(vagrantDir, installRoot string, relPaths []string) error {
+	if len(relPaths) == 0 {
+		return nil
+	}
+
+	var check strings.Builder
+	for i, relPath := range relPaths {
+		if i > 0 {
+			check.WriteString(" && ")
+		}
+		fmt.Fprintf(&check, "test -e '%s'", filepath.Join(installRoot, relPath))
+	}
+
+	cmd := exec.Command("vagrant", "ssh", "-c", check.String())
+	cmd.Dir = vagrantDir
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("expected file(s) missing after install inside vagrant box: %s", strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+func joinArgs(args []string) string {
+	out := ""
+	for i, a := range args {
+		if i > 0 {
+			out += " "
+		}
+		out += a
+	}
+	return out
+}
@@ -0,0 +1,75 @@
+package tester
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/rodchristiansen/gorilla-pkg/packager"
+)
+
+func newTestProject(t *testing.T) string {
+	t.Helper()
+	projectDir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(projectDir, "payload", "bin"), os.ModePerm); err != nil {
+		t.Fatalf("MkdirAll: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(projectDir, "payload", "bin", "app.sh"), []byte("#!/bin/sh\n"), 0o644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+	return projectDir
+}
+
+func TestVerifyPayloadInstalledUsesInstallLocationByDefault(t *testing.T) {
+	projectDir := newTestProject(t)
+	installLocation := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(installLocation, "bin"), os.ModePerm); err != nil {
+		t.Fatalf("MkdirAll: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(installLocation, "bin", "app.sh"), nil, 0o644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	buildInfo := &packager.BuildInfo{InstallLocation: installLocation}
+	if err := verifyPayloadInstalled(projectDir, buildInfo, Options{}); err != nil {
+		t.Errorf("verifyPayloadInstalled: %v", err)
+	}
+}
+
+func TestVerifyPayloadInstalledPrefersInstallDirOverInstallLocation(t *testing.T) {
+	projectDir := newTestProject(t)
+	installDir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(installDir, "bin"), os.ModePerm); err != nil {
+		t.Fatalf("MkdirAll: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(installDir, "bin", "app.sh"), nil, 0o644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	// InstallLocation points somewhere the payload was never written; the
+	// sandboxed opts.InstallDir is where the install actually landed, so the
+	// check must use it instead.
+	buildInfo := &packager.BuildInfo{InstallLocation: filepath.Join(t.TempDir(), "does-not-exist")}
+	if err := verifyPayloadInstalled(projectDir, buildInfo, Options{InstallDir: installDir}); err != nil {
+		t.Errorf("verifyPayloadInstalled: %v", err)
+	}
+}
+
+func TestVerifyPayloadInstalledErrorsOnMissingFile(t *testing.T) {
+	projectDir := newTestProject(t)
+	installDir := t.TempDir()
+
+	buildInfo := &packager.BuildInfo{InstallLocation: installDir}
+	if err := verifyPayloadInstalled(projectDir, buildInfo, Options{}); err == nil {
+		t.Error("verifyPayloadInstalled with no installed files: want error, got nil")
+	}
+}
+
+func TestVerifyPayloadInstalledNoopWithoutPayloadDir(t *testing.T) {
+	projectDir := t.TempDir()
+
+	buildInfo := &packager.BuildInfo{InstallLocation: filepath.Join(t.TempDir(), "does-not-exist")}
+	if err := verifyPayloadInstalled(projectDir, buildInfo, Options{}); err != nil {
+		t.Errorf("verifyPayloadInstalled with no payload directory: want nil, got %v", err)
+	}
+}